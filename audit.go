@@ -0,0 +1,185 @@
+package random
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	math_rand "math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is how much random data has been used. It implements expvar.Var, and the live
+// stats can be published with the same JSON as String with:
+//
+//	expvar.Publish("random", expvar.Func(func() any { return json.RawMessage(random.SecureStats().String()) }))
+type Stats struct {
+	// Calls is the number of reads from the source, such as calls to crypto/rand.Read.
+	Calls uint64
+
+	// Bytes is the number of random bytes read.
+	Bytes uint64
+
+	// Retries is the number of extra reads made because rejection sampling discarded
+	// more random bits than expected, such as when the number of available characters
+	// is not a power of two.
+	Retries uint64
+
+	// Latency is the total time spent reading from the source.
+	Latency time.Duration
+}
+
+// String returns the stats as JSON, which implements expvar.Var.
+func (s Stats) String() string {
+	return fmt.Sprintf(`{"calls":%d,"bytes":%d,"retries":%d,"latency_seconds":%g}`, s.Calls, s.Bytes, s.Retries, s.Latency.Seconds())
+}
+
+// WritePrometheus writes the stats in the Prometheus text exposition format, as counters
+// whose names start with the prefix, such as "random_secure".
+func (s Stats) WritePrometheus(w io.Writer, prefix string) error {
+	metrics := []struct {
+		name, help string
+		value      any
+	}{
+		{"calls_total", "Number of reads from the random source.", s.Calls},
+		{"bytes_total", "Number of random bytes read.", s.Bytes},
+		{"retries_total", "Number of extra reads caused by rejection sampling.", s.Retries},
+		{"latency_seconds_total", "Total time spent reading from the random source.", s.Latency.Seconds()},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s_%s %s\n# TYPE %s_%s counter\n%s_%s %v\n",
+			prefix, m.name, m.help, prefix, m.name, prefix, m.name, m.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statsCounters accumulates Stats atomically.
+type statsCounters struct {
+	calls, bytes, retries, latency atomic.Uint64
+}
+
+// record adds a read of n bytes that started at start.
+func (c *statsCounters) record(n int, start time.Time) {
+	c.latency.Add(uint64(time.Since(start)))
+	c.calls.Add(1)
+	c.bytes.Add(uint64(n))
+}
+
+// stats returns the current Stats.
+func (c *statsCounters) stats() Stats {
+	return Stats{
+		Calls:   c.calls.Load(),
+		Bytes:   c.bytes.Load(),
+		Retries: c.retries.Load(),
+		Latency: time.Duration(c.latency.Load()),
+	}
+}
+
+var (
+	// secureAuditing is true if reads from crypto/rand by the Secure functions are being counted
+	secureAuditing atomic.Bool
+
+	// secureStats counts reads from crypto/rand by the Secure functions
+	secureStats statsCounters
+)
+
+// SetSecureAuditing turns counting of the crypto/rand usage of all Secure functions and
+// SecureRandSource on or off. It is off by default, as timing each read adds a small overhead.
+// Turning it off does not reset the stats.
+func SetSecureAuditing(enabled bool) {
+	secureAuditing.Store(enabled)
+}
+
+// SecureStats returns how much crypto/rand data the Secure functions and SecureRandSource
+// have used while auditing was turned on with SetSecureAuditing.
+func SecureStats() Stats {
+	return secureStats.stats()
+}
+
+// secureRead fills b from crypto/rand, counting the read if auditing is on.
+func secureRead(b []byte) {
+	if !secureAuditing.Load() {
+		if _, err := rand.Read(b); err != nil {
+			panic("random: " + err.Error()) // Impossible
+		}
+		return
+	}
+	start := time.Now()
+	if _, err := rand.Read(b); err != nil {
+		panic("random: " + err.Error()) // Impossible
+	}
+	secureStats.record(len(b), start)
+}
+
+// secureRetry counts an extra read caused by rejection sampling, if auditing is on.
+func secureRetry() {
+	if secureAuditing.Load() {
+		secureStats.retries.Add(1)
+	}
+}
+
+// secureReader is an io.Reader for crypto/rand that counts reads, for functions that need an io.Reader.
+type secureReader struct{}
+
+// Read implements io.Reader
+func (secureReader) Read(b []byte) (int, error) {
+	secureRead(b)
+	return len(b), nil
+}
+
+// AuditedSource wraps a math/rand source, such as one passed to the Pseudo*Rand functions
+// with rand.New, counting how many values are drawn from it and how long that takes.
+// It is thread-safe if the source it wraps is.
+type AuditedSource struct {
+	source math_rand.Source64
+	counts statsCounters
+}
+
+// NewAuditedSource returns an AuditedSource wrapping the source.
+// If the source does not implement math/rand.Source64, Uint64 combines two calls to Int63.
+func NewAuditedSource(source math_rand.Source) *AuditedSource {
+	s64, ok := source.(math_rand.Source64)
+	if !ok {
+		s64 = source64{source}
+	}
+	return &AuditedSource{source: s64}
+}
+
+// source64 adds a Uint64 method to a math/rand.Source, in the same way as *math/rand.Rand does.
+type source64 struct {
+	math_rand.Source
+}
+
+// Uint64 allows implementation of math/rand.Source64
+func (s source64) Uint64() uint64 {
+	return uint64(s.Int63())>>31 | uint64(s.Int63())<<32
+}
+
+// Uint64 allows implementation of math/rand.Source64
+func (s *AuditedSource) Uint64() uint64 {
+	start := time.Now()
+	v := s.source.Uint64()
+	s.counts.record(8, start)
+	return v
+}
+
+// Int63 allows implementation of math/rand.Source
+func (s *AuditedSource) Int63() int64 {
+	start := time.Now()
+	v := s.source.Int63()
+	s.counts.record(8, start)
+	return v
+}
+
+// Seed allows implementation of math/rand.Source
+func (s *AuditedSource) Seed(seed int64) {
+	s.source.Seed(seed)
+}
+
+// Stats returns how much data has been drawn from the source.
+// Retries are always zero, as the source can not tell why values are drawn.
+func (s *AuditedSource) Stats() Stats {
+	return s.counts.stats()
+}
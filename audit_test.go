@@ -0,0 +1,91 @@
+package random_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestSecureStats(t *testing.T) {
+	t.Parallel()
+	random.SetSecureAuditing(true)
+	defer random.SetSecureAuditing(false)
+
+	before := random.SecureStats()
+	random.SecureRandomBytes(100)
+	random.SecureRandomNumber(0, 1000)
+	random.SecureRandomStringBytes(500, []byte("abc"))
+	after := random.SecureStats()
+
+	if after.Calls < before.Calls+3 {
+		t.Errorf("Expecting at least 3 more calls; Got: %d before and %d after", before.Calls, after.Calls)
+	}
+	if after.Bytes < before.Bytes+100 {
+		t.Errorf("Expecting at least 100 more bytes; Got: %d before and %d after", before.Bytes, after.Bytes)
+	}
+	if after.Latency < before.Latency {
+		t.Errorf("Expecting latency to not decrease; Got: %v before and %v after", before.Latency, after.Latency)
+	}
+	if after.Retries < before.Retries {
+		t.Errorf("Expecting retries to not decrease; Got: %d before and %d after", before.Retries, after.Retries)
+	}
+}
+
+func TestStatsExposition(t *testing.T) {
+	t.Parallel()
+	stats := random.Stats{Calls: 3, Bytes: 120, Retries: 1, Latency: 1500}
+
+	var decoded map[string]float64
+	if err := json.Unmarshal([]byte(stats.String()), &decoded); err != nil {
+		t.Fatalf("Expecting JSON; Got: %q: %v", stats.String(), err)
+	}
+	if decoded["calls"] != 3 || decoded["bytes"] != 120 || decoded["retries"] != 1 || decoded["latency_seconds"] != 1.5e-6 {
+		t.Errorf("Expecting matching JSON values; Got: %v", decoded)
+	}
+	if published := expvar.Func(func() any { return json.RawMessage(stats.String()) }).String(); published != stats.String() {
+		t.Errorf("Expecting expvar to publish %s; Got: %s", stats.String(), published)
+	}
+
+	var sb strings.Builder
+	if err := stats.WritePrometheus(&sb, "random_secure"); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# TYPE random_secure_calls_total counter",
+		"random_secure_calls_total 3",
+		"random_secure_bytes_total 120",
+		"random_secure_retries_total 1",
+		"random_secure_latency_seconds_total 1.5e-06",
+	} {
+		if !strings.Contains(sb.String(), line+"\n") {
+			t.Errorf("Expecting line %q; Got:\n%s", line, sb.String())
+		}
+	}
+}
+
+func TestAuditedSource(t *testing.T) {
+	t.Parallel()
+	seed := randtest.Seed(t)
+	audited := random.NewAuditedSource(rand.NewSource(seed))
+	r := rand.New(audited)
+	expected := rand.New(rand.NewSource(seed))
+
+	got := random.PseudoRandomStringRand(r, 50)
+	want := random.PseudoRandomStringRand(expected, 50)
+	if got != want {
+		t.Errorf("Expecting the wrapped source's values %q; Got: %q", want, got)
+	}
+
+	stats := audited.Stats()
+	if stats.Calls == 0 || stats.Bytes != stats.Calls*8 {
+		t.Errorf("Expecting 8 bytes per call; Got: %+v", stats)
+	}
+	if stats.Retries != 0 {
+		t.Errorf("Expecting no retries; Got: %d", stats.Retries)
+	}
+}
@@ -0,0 +1,165 @@
+package random
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ErrBackoffExhausted is returned by Backoff.Wait when MaxAttempts has been reached.
+var ErrBackoffExhausted = errors.New("random: backoff max attempts reached")
+
+// JitterStrategy is an algorithm for randomizing exponential backoff, as described
+// in https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+type JitterStrategy int
+
+const (
+	// FullJitter sleeps a random duration between [0, min(Max, Min * Multiplier^attempt)).
+	FullJitter JitterStrategy = iota
+
+	// EqualJitter sleeps half of min(Max, Min * Multiplier^attempt), plus a random
+	// duration between [0, the other half).
+	EqualJitter
+
+	// DecorrelatedJitter sleeps a random duration between [Min, previous sleep * Multiplier),
+	// capped at Max. The first sleep uses Min as the previous sleep.
+	DecorrelatedJitter
+)
+
+// Backoff computes jittered exponential backoff durations between retries.
+// A Backoff is stateful and is not safe for concurrent use; create one per operation being retried.
+//
+//	backoff := &random.Backoff{Min: 100 * time.Millisecond, Max: 10 * time.Second, MaxAttempts: 5}
+//	for {
+//		if err := doSomething(); err == nil {
+//			break
+//		}
+//		if err := backoff.Wait(ctx); err != nil {
+//			return err
+//		}
+//	}
+type Backoff struct {
+	// Strategy is the jitter algorithm to use. The default is FullJitter.
+	Strategy JitterStrategy
+
+	// Min is the base duration, and Max is the cap that no sleep will exceed.
+	Min time.Duration
+	Max time.Duration
+
+	// Multiplier is how much the backoff grows each attempt.
+	// If zero, it defaults to 2, or 3 for DecorrelatedJitter.
+	// Otherwise it must be at least 1 and finite, or Next will panic.
+	Multiplier float64
+
+	// MaxAttempts is how many sleeps are allowed before giving up. Zero means no limit.
+	MaxAttempts int
+
+	// Source is the random source to use, such as SecureRandSource or a *rand.Rand
+	// with a fixed seed for deterministic tests. It may be changed between calls to Next.
+	// If nil, the global math/rand instance is used.
+	Source rand.Source
+
+	attempt  int
+	previous time.Duration
+}
+
+// Attempt returns how many durations have been returned by Next since the last Reset.
+func (b *Backoff) Attempt() int {
+	return b.attempt
+}
+
+// Reset starts the backoff over from the first attempt, such as after a success.
+func (b *Backoff) Reset() {
+	b.attempt = 0
+	b.previous = 0
+}
+
+// Next returns how long to sleep before the next attempt, or false if MaxAttempts has been reached.
+func (b *Backoff) Next() (time.Duration, bool) {
+	if b.MaxAttempts > 0 && b.attempt >= b.MaxAttempts {
+		return 0, false
+	}
+	if b.Min < 0 || b.Max < b.Min {
+		panic("random: backoff Min must not be negative or greater than Max")
+	}
+	if b.Multiplier != 0 && !(b.Multiplier >= 1 && !math.IsInf(b.Multiplier, 1)) {
+		panic("random: backoff Multiplier must be at least 1 and finite")
+	}
+
+	var sleep time.Duration
+	switch b.Strategy {
+	case FullJitter:
+		sleep = b.between(0, b.exponential())
+
+	case EqualJitter:
+		temp := b.exponential()
+		sleep = temp/2 + b.between(0, temp-temp/2)
+
+	case DecorrelatedJitter:
+		previous := b.previous
+		if previous < b.Min {
+			previous = b.Min
+		}
+		sleep = min(b.Max, b.between(b.Min, multiplyDuration(previous, b.multiplier(3), b.Max)))
+
+	default:
+		panic("random: unknown JitterStrategy")
+	}
+
+	b.attempt++
+	b.previous = sleep
+	return sleep, true
+}
+
+// Wait sleeps for the next backoff duration. It returns ErrBackoffExhausted
+// without sleeping if MaxAttempts has been reached, or the context's error if
+// the context is done before the sleep finishes.
+func (b *Backoff) Wait(ctx context.Context) error {
+	sleep, ok := b.Next()
+	if !ok {
+		return ErrBackoffExhausted
+	}
+	timer := time.NewTimer(sleep)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// exponential returns min(Max, Min * Multiplier^attempt).
+func (b *Backoff) exponential() time.Duration {
+	return multiplyDuration(b.Min, math.Pow(b.multiplier(2), float64(b.attempt)), b.Max)
+}
+
+// multiplier returns the Multiplier, or the default if it is not set.
+func (b *Backoff) multiplier(defaultMultiplier float64) float64 {
+	if b.Multiplier == 0 {
+		return defaultMultiplier
+	}
+	return b.Multiplier
+}
+
+// between returns a random duration between [minInclusive, maxExclusive),
+// or minInclusive if the range is empty.
+func (b *Backoff) between(minInclusive, maxExclusive time.Duration) time.Duration {
+	if maxExclusive <= minInclusive {
+		return minInclusive
+	}
+	if b.Source == nil {
+		return PseudoRandomDuration(minInclusive, maxExclusive)
+	}
+	return PseudoRandomDurationRand(rand.New(b.Source), minInclusive, maxExclusive)
+}
+
+// multiplyDuration returns d * multiplier, capped at limit, without overflowing.
+func multiplyDuration(d time.Duration, multiplier float64, limit time.Duration) time.Duration {
+	if product := float64(d) * multiplier; product < float64(limit) {
+		return time.Duration(product)
+	}
+	return limit
+}
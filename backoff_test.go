@@ -0,0 +1,119 @@
+package random_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/veqryn/go-random"
+)
+
+func TestBackoffNext(t *testing.T) {
+	t.Parallel()
+	for _, strategy := range []random.JitterStrategy{random.FullJitter, random.EqualJitter, random.DecorrelatedJitter} {
+		backoff := &random.Backoff{Strategy: strategy, Min: time.Millisecond, Max: time.Second, MaxAttempts: 50,
+			Source: random.SecureRandSource}
+		for attempt := 0; attempt < 50; attempt++ {
+			sleep, ok := backoff.Next()
+			if !ok {
+				t.Fatalf("Expecting attempt %d to be allowed", attempt)
+			}
+			ceiling := time.Second
+			if attempt < 10 {
+				ceiling = time.Millisecond << attempt
+			}
+			switch strategy {
+			case random.FullJitter:
+				if sleep < 0 || sleep >= ceiling {
+					t.Errorf("Expecting full jitter in [0, %s); Got: %s", ceiling, sleep)
+				}
+			case random.EqualJitter:
+				if sleep < ceiling/2 || sleep >= ceiling {
+					t.Errorf("Expecting equal jitter in [%s, %s); Got: %s", ceiling/2, ceiling, sleep)
+				}
+			case random.DecorrelatedJitter:
+				if sleep < time.Millisecond || sleep > time.Second {
+					t.Errorf("Expecting decorrelated jitter in [1ms, 1s]; Got: %s", sleep)
+				}
+			}
+		}
+		if _, ok := backoff.Next(); ok {
+			t.Error("Expecting max attempts to be reached")
+		}
+		if backoff.Attempt() != 50 {
+			t.Errorf("Expecting 50 attempts; Got: %d", backoff.Attempt())
+		}
+		backoff.Reset()
+		if _, ok := backoff.Next(); !ok {
+			t.Error("Expecting reset to allow more attempts")
+		}
+	}
+}
+
+func TestBackoffDeterministic(t *testing.T) {
+	t.Parallel()
+	first := &random.Backoff{Strategy: random.DecorrelatedJitter, Min: time.Millisecond, Max: time.Minute, Source: rand.New(rand.NewSource(42))}
+	second := &random.Backoff{Strategy: random.DecorrelatedJitter, Min: time.Millisecond, Max: time.Minute, Source: rand.New(rand.NewSource(42))}
+	for i := 0; i < 20; i++ {
+		a, _ := first.Next()
+		b, _ := second.Next()
+		if a != b {
+			t.Errorf("Expecting the same seed to give the same backoff; Got: %s and %s", a, b)
+		}
+	}
+}
+
+func TestBackoffSourceChange(t *testing.T) {
+	t.Parallel()
+	// Changing the Source takes effect on the next call
+	changed := &random.Backoff{Min: time.Millisecond, Max: time.Minute, Source: rand.New(rand.NewSource(1))}
+	changed.Next()
+	changed.Source = rand.New(rand.NewSource(42))
+	changed.Reset()
+	fresh := &random.Backoff{Min: time.Millisecond, Max: time.Minute, Source: rand.New(rand.NewSource(42))}
+	for i := 0; i < 20; i++ {
+		a, _ := changed.Next()
+		b, _ := fresh.Next()
+		if a != b {
+			t.Errorf("Expecting the new Source to be used; Got: %s and %s", a, b)
+		}
+	}
+}
+
+func TestBackoffInvalidMultiplier(t *testing.T) {
+	t.Parallel()
+	for _, multiplier := range []float64{0.5, -2, math.Inf(1), math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expecting panic for Multiplier %v", multiplier)
+				}
+			}()
+			backoff := &random.Backoff{Min: time.Millisecond, Max: time.Second, Multiplier: multiplier}
+			backoff.Next()
+		}()
+	}
+}
+
+func TestBackoffWait(t *testing.T) {
+	t.Parallel()
+	backoff := &random.Backoff{Min: time.Microsecond, Max: time.Millisecond, MaxAttempts: 3}
+	for i := 0; i < 3; i++ {
+		if err := backoff.Wait(context.Background()); err != nil {
+			t.Errorf("Expecting no error; Got: %v", err)
+		}
+	}
+	if err := backoff.Wait(context.Background()); !errors.Is(err, random.ErrBackoffExhausted) {
+		t.Errorf("Expecting ErrBackoffExhausted; Got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	backoff = &random.Backoff{Strategy: random.EqualJitter, Min: time.Hour, Max: time.Hour}
+	if err := backoff.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expecting context.Canceled; Got: %v", err)
+	}
+}
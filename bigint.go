@@ -0,0 +1,257 @@
+package random
+
+import (
+	"encoding/binary"
+	"math/big"
+	math_rand "math/rand"
+)
+
+// SecureBigInt uses crypto/rand to return a number between [minInclusive, maxExclusive).
+// If max - min <= 0, this will panic.
+func SecureBigInt(minInclusive, maxExclusive *big.Int) *big.Int {
+	return bigIntBase(secureFill, minInclusive, maxExclusive)
+}
+
+// PseudoBigInt uses math/rand to return a number between [minInclusive, maxExclusive).
+// If max - min <= 0, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoBigInt(minInclusive, maxExclusive *big.Int) *big.Int {
+	return bigIntBase(pseudoFill(math_rand.Uint64), minInclusive, maxExclusive)
+}
+
+// PseudoBigIntRand uses math/rand to return a number between [minInclusive, maxExclusive).
+// If max - min <= 0, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoBigIntRand(rand *math_rand.Rand, minInclusive, maxExclusive *big.Int) *big.Int {
+	return bigIntBase(pseudoFill(rand.Uint64), minInclusive, maxExclusive)
+}
+
+// SecureBitLengthInt uses crypto/rand to return a number with exactly bitLength bits,
+// ie: between [2^(bitLength-1), 2^bitLength).
+// If bitLength is less than 1, this will panic.
+func SecureBitLengthInt(bitLength int) *big.Int {
+	return bitLengthIntBase(secureFill, bitLength)
+}
+
+// PseudoBitLengthInt uses math/rand to return a number with exactly bitLength bits,
+// ie: between [2^(bitLength-1), 2^bitLength).
+// If bitLength is less than 1, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoBitLengthInt(bitLength int) *big.Int {
+	return bitLengthIntBase(pseudoFill(math_rand.Uint64), bitLength)
+}
+
+// PseudoBitLengthIntRand uses math/rand to return a number with exactly bitLength bits,
+// ie: between [2^(bitLength-1), 2^bitLength).
+// If bitLength is less than 1, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoBitLengthIntRand(rand *math_rand.Rand, bitLength int) *big.Int {
+	return bitLengthIntBase(pseudoFill(rand.Uint64), bitLength)
+}
+
+// SecurePrime uses crypto/rand to return a probable prime with exactly bitLength bits.
+// The chance of it not being prime is less than 2^-40, and no composite has been found to pass.
+// If bitLength is less than 2, this will panic.
+func SecurePrime(bitLength int) *big.Int {
+	return primeBase(secureFill, bitLength)
+}
+
+// PseudoPrime uses math/rand to return a probable prime with exactly bitLength bits.
+// If bitLength is less than 2, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoPrime(bitLength int) *big.Int {
+	return primeBase(pseudoFill(math_rand.Uint64), bitLength)
+}
+
+// PseudoPrimeRand uses math/rand to return a probable prime with exactly bitLength bits.
+// If bitLength is less than 2, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoPrimeRand(rand *math_rand.Rand, bitLength int) *big.Int {
+	return primeBase(pseudoFill(rand.Uint64), bitLength)
+}
+
+// SecureSafePrime uses crypto/rand to return a probable safe prime p with exactly bitLength bits,
+// where (p-1)/2 is also prime, such as for Diffie-Hellman groups.
+// Safe primes are rare, so this can take many seconds for bit lengths over 1024.
+// If bitLength is less than 3, this will panic.
+func SecureSafePrime(bitLength int) *big.Int {
+	return safePrimeBase(secureFill, bitLength)
+}
+
+// PseudoSafePrime uses math/rand to return a probable safe prime p with exactly bitLength bits,
+// where (p-1)/2 is also prime.
+// If bitLength is less than 3, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoSafePrime(bitLength int) *big.Int {
+	return safePrimeBase(pseudoFill(math_rand.Uint64), bitLength)
+}
+
+// PseudoSafePrimeRand uses math/rand to return a probable safe prime p with exactly bitLength bits,
+// where (p-1)/2 is also prime.
+// If bitLength is less than 3, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoSafePrimeRand(rand *math_rand.Rand, bitLength int) *big.Int {
+	return safePrimeBase(pseudoFill(rand.Uint64), bitLength)
+}
+
+// SecureCoprimeInt uses crypto/rand to return a random element of the multiplicative group
+// of integers modulo n (Z_n^*), ie: a number between [1, n) that is coprime to n.
+// If n is less than 2, this will panic.
+func SecureCoprimeInt(n *big.Int) *big.Int {
+	return coprimeIntBase(secureFill, n)
+}
+
+// PseudoCoprimeInt uses math/rand to return a random element of the multiplicative group
+// of integers modulo n (Z_n^*), ie: a number between [1, n) that is coprime to n.
+// If n is less than 2, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoCoprimeInt(n *big.Int) *big.Int {
+	return coprimeIntBase(pseudoFill(math_rand.Uint64), n)
+}
+
+// PseudoCoprimeIntRand uses math/rand to return a random element of the multiplicative group
+// of integers modulo n (Z_n^*), ie: a number between [1, n) that is coprime to n.
+// If n is less than 2, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoCoprimeIntRand(rand *math_rand.Rand, n *big.Int) *big.Int {
+	return coprimeIntBase(pseudoFill(rand.Uint64), n)
+}
+
+// pseudoFill returns a function that fills a byte slice with data from randUint64.
+// Unlike (*math/rand.Rand).Read, it keeps no leftover bytes between calls,
+// so the results only depend on the state of the source.
+func pseudoFill(randUint64 func() uint64) func([]byte) {
+	return func(b []byte) {
+		var buf [8]byte
+		for len(b) > 0 {
+			binary.LittleEndian.PutUint64(buf[:], randUint64())
+			b = b[copy(b, buf[:]):]
+		}
+	}
+}
+
+// bigIntBase returns a uniformly random number between [minInclusive, maxExclusive).
+func bigIntBase(fill func([]byte), minInclusive, maxExclusive *big.Int) *big.Int {
+	n := new(big.Int).Sub(maxExclusive, minInclusive)
+	if n.Sign() <= 0 {
+		panic("random: maxExclusive must be greater than minInclusive")
+	}
+	result := uniformBigInt(fill, n)
+	return result.Add(result, minInclusive)
+}
+
+// uniformBigInt returns a uniformly random number between [0, n), where n is positive,
+// by masking random bytes to the bit length of n - 1, and rejecting values that are too large.
+// At least half of all attempts succeed.
+func uniformBigInt(fill func([]byte), n *big.Int) *big.Int {
+	bitLength := new(big.Int).Sub(n, big.NewInt(1)).BitLen()
+	if bitLength == 0 {
+		return new(big.Int)
+	}
+	b := make([]byte, (bitLength+7)/8)
+	topMask := byte(1<<((bitLength-1)%8+1) - 1)
+	result := new(big.Int)
+	for {
+		fill(b)
+		b[0] &= topMask
+		if result.SetBytes(b).Cmp(n) < 0 {
+			clear(b)
+			return result
+		}
+	}
+}
+
+// bitLengthIntBase returns a uniformly random number with exactly bitLength bits.
+func bitLengthIntBase(fill func([]byte), bitLength int) *big.Int {
+	if bitLength < 1 {
+		panic("random: bitLength must be greater than zero")
+	}
+	b := make([]byte, (bitLength+7)/8)
+	fill(b)
+	b[0] &= byte(1<<((bitLength-1)%8+1) - 1)
+	b[0] |= byte(1 << ((bitLength - 1) % 8))
+	result := new(big.Int).SetBytes(b)
+	clear(b)
+	return result
+}
+
+// primeRounds is the number of Miller-Rabin rounds used by ProbablyPrime, in addition
+// to the Baillie-PSW test it always does, which has no known composites that pass it.
+const primeRounds = 20
+
+// smallPrimes are the odd primes whose product fits in a uint64, used to quickly reject candidates.
+var smallPrimes = [...]uint64{3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53}
+
+// smallPrimesProduct is the product of smallPrimes.
+var smallPrimesProduct = new(big.Int).SetUint64(16294579238595022365)
+
+// primeBase returns a random probable prime with exactly bitLength bits.
+func primeBase(fill func([]byte), bitLength int) *big.Int {
+	if bitLength < 2 {
+		panic("random: bitLength must be at least 2 for primes")
+	}
+	for {
+		p := bitLengthIntBase(fill, bitLength)
+		p.SetBit(p, 0, 1)
+		if p.ProbablyPrime(primeRounds) {
+			return p
+		}
+	}
+}
+
+// safePrimeBase returns a random probable safe prime with exactly bitLength bits.
+// Candidates are first checked against small primes, to avoid the expensive tests for most of them.
+func safePrimeBase(fill func([]byte), bitLength int) *big.Int {
+	if bitLength < 3 {
+		panic("random: bitLength must be at least 3 for safe primes")
+	}
+	mod := new(big.Int)
+	for {
+		// q is the Sophie Germain prime, and p = 2q + 1 is the safe prime
+		q := bitLengthIntBase(fill, bitLength-1)
+		q.SetBit(q, 0, 1)
+		if q.BitLen() > 6 {
+			m := mod.Mod(q, smallPrimesProduct).Uint64()
+			divisible := false
+			for _, prime := range smallPrimes {
+				// q is divisible by prime, or p is, which is when q mod prime is (prime - 1) / 2
+				if r := m % prime; r == 0 || r == (prime-1)/2 {
+					divisible = true
+					break
+				}
+			}
+			if divisible {
+				continue
+			}
+		}
+		p := new(big.Int).Lsh(q, 1)
+		p.SetBit(p, 0, 1)
+		if q.ProbablyPrime(primeRounds) && p.ProbablyPrime(primeRounds) {
+			return p
+		}
+	}
+}
+
+// coprimeIntBase returns a random number between [1, n) that is coprime to n.
+func coprimeIntBase(fill func([]byte), n *big.Int) *big.Int {
+	if n.Cmp(big.NewInt(2)) < 0 {
+		panic("random: n must be at least 2")
+	}
+	gcd := new(big.Int)
+	for {
+		x := uniformBigInt(fill, n)
+		if x.Sign() > 0 && gcd.GCD(nil, nil, x, n).Cmp(big.NewInt(1)) == 0 {
+			return x
+		}
+	}
+}
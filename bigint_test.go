@@ -0,0 +1,99 @@
+package random_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestSecureBigInt(t *testing.T) {
+	t.Parallel()
+	min, _ := new(big.Int).SetString("-100000000000000000000000000000", 10)
+	max, _ := new(big.Int).SetString("100000000000000000000000000001", 10)
+	for i := 0; i < 1000; i++ {
+		n := random.SecureBigInt(min, max)
+		if n.Cmp(min) < 0 || n.Cmp(max) >= 0 {
+			t.Errorf("Expecting number between [%s, %s); Got: %s", min, max, n)
+		}
+	}
+
+	// Every value in a small range that is not a power of two is reached
+	seen := map[int64]int{}
+	source := randtest.Rand(t)
+	for i := 0; i < 6000; i++ {
+		seen[random.PseudoBigIntRand(source, big.NewInt(10), big.NewInt(16)).Int64()]++
+	}
+	for n := int64(10); n < 16; n++ {
+		if seen[n] < 800 || seen[n] > 1200 {
+			t.Errorf("Expecting roughly 1000 of %d; Got: %v", n, seen)
+		}
+	}
+	if n := random.PseudoBigInt(big.NewInt(5), big.NewInt(6)); n.Int64() != 5 {
+		t.Errorf("Expecting 5; Got: %s", n)
+	}
+}
+
+func TestSecureBitLengthInt(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	for bitLength := 1; bitLength <= 300; bitLength++ {
+		if n := random.SecureBitLengthInt(bitLength); n.BitLen() != bitLength {
+			t.Errorf("Expecting bit length %d; Got: %d", bitLength, n.BitLen())
+		}
+		if n := random.PseudoBitLengthIntRand(source, bitLength); n.BitLen() != bitLength {
+			t.Errorf("Expecting bit length %d; Got: %d", bitLength, n.BitLen())
+		}
+	}
+}
+
+func TestSecurePrime(t *testing.T) {
+	t.Parallel()
+	for _, bitLength := range []int{2, 3, 8, 64, 256} {
+		p := random.SecurePrime(bitLength)
+		if p.BitLen() != bitLength || !p.ProbablyPrime(20) {
+			t.Errorf("Expecting %d bit prime; Got: %s", bitLength, p)
+		}
+	}
+	for _, bitLength := range []int{3, 4, 10, 64, 128} {
+		p := random.SecureSafePrime(bitLength)
+		q := new(big.Int).Rsh(p, 1)
+		if p.BitLen() != bitLength || !p.ProbablyPrime(20) || !q.ProbablyPrime(20) {
+			t.Errorf("Expecting %d bit safe prime; Got: %s", bitLength, p)
+		}
+	}
+}
+
+func TestPseudoPrimeRandDeterministic(t *testing.T) {
+	t.Parallel()
+	seed := randtest.Seed(t)
+	a := random.PseudoPrimeRand(rand.New(rand.NewSource(seed)), 128)
+	b := random.PseudoPrimeRand(rand.New(rand.NewSource(seed)), 128)
+	if a.Cmp(b) != 0 {
+		t.Errorf("Expecting the same seed to give the same prime; Got: %s and %s", a, b)
+	}
+	a = random.PseudoSafePrimeRand(rand.New(rand.NewSource(seed)), 64)
+	b = random.PseudoSafePrimeRand(rand.New(rand.NewSource(seed)), 64)
+	if a.Cmp(b) != 0 {
+		t.Errorf("Expecting the same seed to give the same safe prime; Got: %s and %s", a, b)
+	}
+}
+
+func TestSecureCoprimeInt(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	n := big.NewInt(2 * 3 * 5 * 7 * 11)
+	one := big.NewInt(1)
+	for i := 0; i < 1000; i++ {
+		for _, x := range []*big.Int{random.SecureCoprimeInt(n), random.PseudoCoprimeIntRand(source, n)} {
+			if x.Sign() <= 0 || x.Cmp(n) >= 0 || new(big.Int).GCD(nil, nil, x, n).Cmp(one) != 0 {
+				t.Errorf("Expecting element of Z_%s^*; Got: %s", n, x)
+			}
+		}
+	}
+	if x := random.PseudoCoprimeInt(big.NewInt(2)); x.Cmp(one) != 0 {
+		t.Errorf("Expecting 1; Got: %s", x)
+	}
+}
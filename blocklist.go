@@ -0,0 +1,241 @@
+package random
+
+import (
+	_ "embed"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+//go:embed blocklist.txt
+var defaultBlocklistText string
+
+// DefaultBlocklist contains common English profanity, and can be used to filter
+// human-visible random strings and codes.
+var DefaultBlocklist = NewBlocklist(DefaultBlocklistWords()...)
+
+// DefaultBlocklistWords returns the words in DefaultBlocklist, so that they can be
+// combined with caller provided words to create a new Blocklist.
+func DefaultBlocklistWords() []string {
+	var words []string
+	for _, line := range strings.Split(defaultBlocklistText, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words
+}
+
+// Blocklist matches words as case-insensitive substrings, after normalizing
+// leetspeak (ie: "5h1t" matches "shit").
+// It is implemented as an Aho-Corasick automaton, so matching takes linear time
+// regardless of the number of words. A Blocklist is safe for concurrent use.
+type Blocklist struct {
+	// transitions is the automaton's transition table, indexed by state then symbol
+	transitions [][blocklistSymbols]int32
+
+	// blocked is true for each state in which a word has been matched
+	blocked []bool
+}
+
+// blocklistSymbols is the number of symbols the automaton works with:
+// the letters a through z, and one symbol for every other character.
+const blocklistSymbols = 27
+
+// blocklistOther is the symbol for any character that is not a letter after normalization.
+const blocklistOther = blocklistSymbols - 1
+
+// leetspeak maps characters commonly substituted for letters back to those letters.
+var leetspeak = map[byte]byte{
+	'0': 'o', '1': 'i', '2': 'z', '3': 'e', '4': 'a', '5': 's', '6': 'g', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'l', '+': 't',
+}
+
+// blocklistSymbol returns the automaton symbol for a character.
+func blocklistSymbol(c byte) int {
+	if l, ok := leetspeak[c]; ok {
+		c = l
+	}
+	switch {
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a')
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A')
+	}
+	return blocklistOther
+}
+
+// NewBlocklist returns a Blocklist that matches any of the words.
+// Words are normalized the same way as the strings they are matched against,
+// and any words that contain no letters are ignored.
+func NewBlocklist(words ...string) *Blocklist {
+
+	// Build a trie of the words
+	b := &Blocklist{transitions: make([][blocklistSymbols]int32, 1), blocked: make([]bool, 1)}
+	for _, word := range words {
+		state := int32(0)
+		letters := 0
+		for i := 0; i < len(word); i++ {
+			sym := blocklistSymbol(word[i])
+			if sym == blocklistOther {
+				continue
+			}
+			letters++
+			if b.transitions[state][sym] == 0 {
+				b.transitions = append(b.transitions, [blocklistSymbols]int32{})
+				b.blocked = append(b.blocked, false)
+				b.transitions[state][sym] = int32(len(b.transitions) - 1)
+			}
+			state = b.transitions[state][sym]
+		}
+		if letters > 0 {
+			b.blocked[state] = true
+		}
+	}
+
+	// Convert the trie into a complete automaton, breadth first, using failure links
+	fail := make([]int32, len(b.transitions))
+	queue := make([]int32, 0, len(b.transitions))
+	for sym := 0; sym < blocklistSymbols; sym++ {
+		if next := b.transitions[0][sym]; next != 0 {
+			queue = append(queue, next)
+		}
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		b.blocked[state] = b.blocked[state] || b.blocked[fail[state]]
+		for sym := 0; sym < blocklistSymbols; sym++ {
+			if next := b.transitions[state][sym]; next != 0 {
+				fail[next] = b.transitions[fail[state]][sym]
+				queue = append(queue, next)
+			} else {
+				b.transitions[state][sym] = b.transitions[fail[state]][sym]
+			}
+		}
+	}
+	return b
+}
+
+// Contains returns true if s contains any of the blocklist's words.
+func (b *Blocklist) Contains(s string) bool {
+	state := int32(0)
+	for i := 0; i < len(s); i++ {
+		state = b.transitions[state][blocklistSymbol(s[i])]
+		if b.blocked[state] {
+			return true
+		}
+	}
+	return false
+}
+
+// EntropyCost returns how many bits of entropy are lost by filtering random strings
+// of the given length, made from the available character bytes, with this blocklist.
+// A string of length characters normally has length*log2(len(availableCharBytes)) bits
+// of entropy; the filtered strings have that many bits minus the cost.
+// If every possible string is blocked, this returns +Inf.
+func (b *Blocklist) EntropyCost(length int, availableCharBytes []byte) float64 {
+	return -math.Log2(b.acceptance(length, availableCharBytes))
+}
+
+// acceptance returns the probability that a uniformly random string of the given
+// length, made from the available character bytes, is not blocked.
+func (b *Blocklist) acceptance(length int, availableCharBytes []byte) float64 {
+
+	// weights is the probability of each symbol being chosen
+	var weights [blocklistSymbols]float64
+	for _, c := range availableCharBytes {
+		weights[blocklistSymbol(c)]++
+	}
+	for sym := range weights {
+		weights[sym] /= float64(len(availableCharBytes))
+	}
+
+	// probabilities is the probability of being in each state without having been blocked yet
+	probabilities := make([]float64, len(b.transitions))
+	next := make([]float64, len(b.transitions))
+	probabilities[0] = 1
+	for i := 0; i < length; i++ {
+		clear(next)
+		for state, p := range probabilities {
+			if p == 0 {
+				continue
+			}
+			for sym, w := range weights {
+				if w != 0 {
+					next[b.transitions[state][sym]] += p * w
+				}
+			}
+		}
+		for state := range next {
+			if b.blocked[state] {
+				next[state] = 0
+			}
+		}
+		probabilities, next = next, probabilities
+	}
+
+	total := 0.0
+	for _, p := range probabilities {
+		total += p
+	}
+	return total
+}
+
+// blocklistMaxAttempts is how many strings get generated and blocked before
+// checking whether it is possible for any string to not be blocked.
+const blocklistMaxAttempts = 100
+
+// filteredStringBase calls generate until it returns a string not contained in the blocklist.
+// Rejecting blocked strings, instead of altering them, keeps the distribution
+// uniform over the strings that are not blocked.
+// If every possible string is blocked, this will panic.
+func filteredStringBase(blocklist *Blocklist, length int, availableCharBytes []byte, generate func() string) string {
+	for attempted := 1; ; attempted++ {
+		result := generate()
+		if !blocklist.Contains(result) {
+			return result
+		}
+		if attempted == blocklistMaxAttempts && blocklist.acceptance(length, availableCharBytes) == 0 {
+			panic("random: every possible string is blocked")
+		}
+	}
+}
+
+// SecureRandomStringBytesFiltered uses crypto/rand to return a random string of given
+// length made from the available character bytes, that does not contain any of
+// the words in the blocklist.
+// If the available character bytes slice is empty or greater than 256 in length, length is negative,
+// or every possible string is blocked, this will panic.
+func SecureRandomStringBytesFiltered(length int, availableCharBytes []byte, blocklist *Blocklist) string {
+	return filteredStringBase(blocklist, length, availableCharBytes, func() string {
+		return SecureRandomStringBytes(length, availableCharBytes)
+	})
+}
+
+// PseudoRandomStringBytesFiltered uses math/rand to return a random string of given
+// length made from the available character bytes, that does not contain any of
+// the words in the blocklist.
+// If the available character bytes slice is empty, length is negative,
+// or every possible string is blocked, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomStringBytesFiltered(length int, availableCharBytes []byte, blocklist *Blocklist) string {
+	return filteredStringBase(blocklist, length, availableCharBytes, func() string {
+		return pseudoRandomStringBytesBase(rand.Uint64, length, availableCharBytes)
+	})
+}
+
+// PseudoRandomStringBytesFilteredRand uses math/rand to return a random string of given
+// length made from the available character bytes, that does not contain any of
+// the words in the blocklist.
+// If the available character bytes slice is empty, length is negative,
+// or every possible string is blocked, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomStringBytesFilteredRand(rand *rand.Rand, length int, availableCharBytes []byte, blocklist *Blocklist) string {
+	return filteredStringBase(blocklist, length, availableCharBytes, func() string {
+		return pseudoRandomStringBytesBase(rand.Uint64, length, availableCharBytes)
+	})
+}
@@ -0,0 +1,96 @@
+package random_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestBlocklistContains(t *testing.T) {
+	t.Parallel()
+	blocklist := random.NewBlocklist("shit", "ass", "BAD")
+	for _, s := range []string{"shit", "SHIT", "xx5h1txx", "$H!7", "a55", "grass", "xBAD", "b4d"} {
+		if !blocklist.Contains(s) {
+			t.Errorf("Expecting %q to be blocked", s)
+		}
+	}
+	for _, s := range []string{"", "shi-t", "as", "shot", "bat", "ba_d"} {
+		if blocklist.Contains(s) {
+			t.Errorf("Expecting %q to not be blocked", s)
+		}
+	}
+	if !random.DefaultBlocklist.Contains("XYFUCKZ") {
+		t.Error("Expecting default blocklist to block XYFUCKZ")
+	}
+}
+
+func TestBlocklistEntropyCost(t *testing.T) {
+	t.Parallel()
+	if cost := random.NewBlocklist().EntropyCost(16, random.AlphaNumericBytes); math.Abs(cost) > 1e-9 {
+		t.Errorf("Expecting empty blocklist to cost 0 bits; Got: %f", cost)
+	}
+	// Blocking "a" over "ab" leaves only "bbbb", which is 4 bits lost out of 4
+	if cost := random.NewBlocklist("a").EntropyCost(4, []byte("ab")); math.Abs(cost-4) > 1e-9 {
+		t.Errorf("Expecting 4 bits lost; Got: %f", cost)
+	}
+	// Blocking "aa" over "ab" with length 3 leaves aba, abb, bab, bba, bbb
+	if cost := random.NewBlocklist("aa").EntropyCost(3, []byte("ab")); math.Abs(cost-math.Log2(8.0/5.0)) > 1e-9 {
+		t.Errorf("Expecting %f bits lost; Got: %f", math.Log2(8.0/5.0), cost)
+	}
+	if cost := random.NewBlocklist("a", "b").EntropyCost(1, []byte("ab")); !math.IsInf(cost, 1) {
+		t.Errorf("Expecting infinite cost; Got: %f", cost)
+	}
+}
+
+func TestSecureRandomStringBytesFiltered(t *testing.T) {
+	t.Parallel()
+	blocklist := random.NewBlocklist("a")
+	for length := 0; length <= 32; length++ {
+		result := random.SecureRandomStringBytesFiltered(length, random.HexBytes, blocklist)
+		if len(result) != length || blocklist.Contains(result) {
+			t.Errorf("Expecting unblocked string of length %d; Got: %q", length, result)
+		}
+	}
+}
+
+func TestPseudoRandomStringBytesFilteredRand(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	blocklist := random.NewBlocklist("aa")
+	counts := map[string]int{}
+	for i := 0; i < 5000; i++ {
+		counts[random.PseudoRandomStringBytesFilteredRand(source, 3, []byte("ab"), blocklist)]++
+	}
+	// Remaining strings should be uniformly distributed
+	if len(counts) != 5 {
+		t.Errorf("Expecting 5 distinct strings; Got: %v", counts)
+	}
+	for s, count := range counts {
+		if count < 800 || count > 1200 {
+			t.Errorf("Expecting roughly 1000 of %q; Got: %d", s, count)
+		}
+	}
+}
+
+func TestFilteredAllBlocked(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Error("Expecting panic when every string is blocked")
+		}
+	}()
+	random.PseudoRandomStringBytesFiltered(2, []byte("ab"), random.NewBlocklist("a", "b"))
+}
+
+func TestSecureFormattedCodeBlocklist(t *testing.T) {
+	t.Parallel()
+	format := random.CodeFormat{AvailableCharBytes: []byte("AB"), Length: 8, GroupSize: 2, Separator: "-",
+		Blocklist: random.NewBlocklist("aa")}
+	for i := 0; i < 100; i++ {
+		if code := random.SecureFormattedCode(format); format.Blocklist.Contains(code) {
+			t.Errorf("Expecting code to not be blocked; Got: %s", code)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package random
+
+import (
+	"math/bits"
+	"math/rand"
+	"strings"
+	"unicode"
+)
+
+// CheckCharacter is an algorithm used to compute a check character that gets
+// appended to a formatted code, allowing typos to be detected.
+type CheckCharacter int
+
+const (
+	// CheckNone does not append a check character.
+	CheckNone CheckCharacter = iota
+
+	// CheckLuhn appends a Luhn mod N check character, where N is the number of
+	// available characters. It detects all single character errors and most
+	// transpositions of adjacent characters.
+	CheckLuhn
+
+	// CheckDamm appends a Damm check character, which detects all single character
+	// errors and all transpositions of adjacent characters.
+	// The number of available characters must be odd, 10, or a power of two greater than 2.
+	CheckDamm
+)
+
+// CodeFormat describes a human-friendly code, such as a gift card number, MFA
+// recovery code, or support PIN (ie: "ABCD-EFGH-JKLM").
+type CodeFormat struct {
+	// AvailableCharBytes are the characters the code is made from, such as UnambiguousBytes.
+	// It must not be empty or be longer than 256 bytes.
+	AvailableCharBytes []byte
+
+	// Length is the number of random characters, not counting any check character or separators.
+	Length int
+
+	// GroupSize is how many characters are placed between each separator.
+	// The check character, if any, counts towards the final group.
+	// Zero means the code is not grouped.
+	GroupSize int
+
+	// Separator is placed between each group of characters, such as "-".
+	Separator string
+
+	// Check is the check character algorithm, if any.
+	Check CheckCharacter
+
+	// Blocklist, if not nil, causes codes containing any of its words to be regenerated.
+	// The check character and separators are included when matching.
+	// If every possible code, or nearly every code, is blocked, generating a code will panic.
+	Blocklist *Blocklist
+}
+
+// SecureFormattedCode uses crypto/rand to return a random code in the given format.
+// If the format is invalid, this will panic.
+func SecureFormattedCode(format CodeFormat) string {
+	return formattedCodeBase(format, func() string {
+		return SecureRandomStringBytes(format.Length, format.AvailableCharBytes)
+	})
+}
+
+// PseudoFormattedCode uses math/rand to return a random code in the given format.
+// If the format is invalid, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoFormattedCode(format CodeFormat) string {
+	return formattedCodeBase(format, func() string {
+		return pseudoRandomStringBytesBase(rand.Uint64, format.Length, format.AvailableCharBytes)
+	})
+}
+
+// PseudoFormattedCodeRand uses math/rand to return a random code in the given format.
+// If the format is invalid, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoFormattedCodeRand(rand *rand.Rand, format CodeFormat) string {
+	return formattedCodeBase(format, func() string {
+		return pseudoRandomStringBytesBase(rand.Uint64, format.Length, format.AvailableCharBytes)
+	})
+}
+
+// formattedCodeBase appends the check character to the random characters from generate,
+// then groups them, regenerating them if the result is blocked.
+func formattedCodeBase(format CodeFormat, generate func() string) string {
+	indices := codeIndices(format.AvailableCharBytes)
+	if format.GroupSize < 0 {
+		panic("random: GroupSize can not be negative")
+	}
+	build := func() string {
+		randomChars := generate()
+		if format.Check != CheckNone {
+			randomChars += string(format.AvailableCharBytes[checkCharacterIndex(format, indices, randomChars)])
+		}
+		return groupCode(format, randomChars)
+	}
+	if format.Blocklist == nil {
+		return build()
+	}
+
+	// The check character and separators are matched too, so the chance of a code not being
+	// blocked can not be calculated from the random characters alone, as filteredStringBase does.
+	// Instead, give up after so many blocked codes that any format with a real chance of
+	// success would practically never reach it.
+	for attempted := 0; attempted < codeBlocklistMaxAttempts; attempted++ {
+		if code := build(); !format.Blocklist.Contains(code) {
+			return code
+		}
+	}
+	panic("random: every possible code is blocked")
+}
+
+// codeBlocklistMaxAttempts is how many blocked codes formattedCodeBase generates before giving up.
+// A format that blocks 99% of codes fails this often with a probability of less than 2^-1000.
+const codeBlocklistMaxAttempts = 100_000
+
+// NormalizeCode returns the code in its canonical format, and whether it could be normalized.
+// It tolerates surrounding and interior whitespace, missing or misplaced separators
+// and dashes, and the wrong letter case when the available characters are all one case.
+// If the available characters exclude them, the Crockford substitutions of O for 0,
+// and I or L for 1, are also accepted.
+// It does not check that the check character is correct; use VerifyCode for that.
+func NormalizeCode(format CodeFormat, code string) (string, bool) {
+	indices := codeIndices(format.AvailableCharBytes)
+	expected := format.Length
+	if format.Check != CheckNone {
+		expected++
+	}
+
+	if format.Separator != "" {
+		code = strings.ReplaceAll(code, format.Separator, "")
+	}
+
+	chars := make([]byte, 0, expected)
+	for _, r := range code {
+		if unicode.IsSpace(r) || (r == '-' && indices['-'] < 0) {
+			continue
+		}
+		c, ok := normalizeCodeChar(indices, r)
+		if !ok || len(chars) == expected {
+			return "", false
+		}
+		chars = append(chars, c)
+	}
+	if len(chars) != expected {
+		return "", false
+	}
+	return groupCode(format, string(chars)), true
+}
+
+// VerifyCode returns true if the code, once normalized, has the correct length,
+// only contains the available characters, and has a valid check character.
+func VerifyCode(format CodeFormat, code string) bool {
+	normalized, ok := NormalizeCode(format, code)
+	if !ok {
+		return false
+	}
+	if format.Check == CheckNone {
+		return true
+	}
+
+	if format.Separator != "" {
+		normalized = strings.ReplaceAll(normalized, format.Separator, "")
+	}
+	indices := codeIndices(format.AvailableCharBytes)
+	last := len(normalized) - 1
+	return int(indices[normalized[last]]) == checkCharacterIndex(format, indices, normalized[:last])
+}
+
+// codeIndices returns a lookup table from each byte to its index in the available
+// characters, or -1 if it is not available.
+// If the available character bytes slice is empty or greater than 256 in length, this will panic.
+func codeIndices(availableCharBytes []byte) [256]int16 {
+	if len(availableCharBytes) == 0 || len(availableCharBytes) > 256 {
+		panic("random: availableCharBytes must not be empty or be longer than 256 bytes")
+	}
+	var indices [256]int16
+	for i := range indices {
+		indices[i] = -1
+	}
+	for i, c := range availableCharBytes {
+		indices[c] = int16(i)
+	}
+	return indices
+}
+
+// normalizeCodeChar returns the available character that r stands for, if any.
+func normalizeCodeChar(indices [256]int16, r rune) (byte, bool) {
+	for _, candidate := range [...]rune{r, unicode.ToUpper(r), unicode.ToLower(r), crockfordSubstitute(r)} {
+		if candidate >= 0 && candidate < 256 && indices[candidate] >= 0 {
+			return byte(candidate), true
+		}
+	}
+	return 0, false
+}
+
+// crockfordSubstitute returns the digit a commonly confused letter stands for,
+// following Crockford's base32 decoding rules.
+func crockfordSubstitute(r rune) rune {
+	switch unicode.ToUpper(r) {
+	case 'O':
+		return '0'
+	case 'I', 'L':
+		return '1'
+	}
+	return r
+}
+
+// groupCode inserts the separator between each group of characters.
+func groupCode(format CodeFormat, chars string) string {
+	if format.GroupSize == 0 || len(chars) <= format.GroupSize {
+		return chars
+	}
+	var sb strings.Builder
+	sb.Grow(len(chars) + len(format.Separator)*(len(chars)/format.GroupSize))
+	for i := 0; i < len(chars); i += format.GroupSize {
+		if i > 0 {
+			sb.WriteString(format.Separator)
+		}
+		sb.WriteString(chars[i:min(i+format.GroupSize, len(chars))])
+	}
+	return sb.String()
+}
+
+// checkCharacterIndex returns the index into the available characters of the check character for chars.
+func checkCharacterIndex(format CodeFormat, indices [256]int16, chars string) int {
+	switch format.Check {
+	case CheckLuhn:
+		return luhnCheckIndex(len(format.AvailableCharBytes), indices, chars)
+	case CheckDamm:
+		return dammCheckIndex(len(format.AvailableCharBytes), indices, chars)
+	}
+	panic("random: unknown CheckCharacter")
+}
+
+// luhnCheckIndex implements the Luhn mod N algorithm.
+func luhnCheckIndex(n int, indices [256]int16, chars string) int {
+	factor := 2
+	sum := 0
+	for i := len(chars) - 1; i >= 0; i-- {
+		addend := factor * int(indices[chars[i]])
+		sum += addend/n + addend%n
+		factor = 3 - factor // Alternate between 2 and 1
+	}
+	return (n - sum%n) % n
+}
+
+// dammCheckIndex implements the Damm algorithm, using a totally anti-symmetric
+// quasigroup of order n.
+func dammCheckIndex(n int, indices [256]int16, chars string) int {
+	op := dammOperation(n)
+	interim := 0
+	for i := 0; i < len(chars); i++ {
+		interim = op(interim, int(indices[chars[i]]))
+	}
+	// The check character is the one that brings the interim back to zero
+	for check := 0; check < n; check++ {
+		if op(interim, check) == 0 {
+			return check
+		}
+	}
+	panic("random: no Damm check character found") // Impossible
+}
+
+// dammOperation returns the operation of a totally anti-symmetric quasigroup of order n.
+// For n equal to 10 this is Damm's original table. For odd n it is (2a + b) mod n.
+// For powers of two it is (2a XOR b) where 2a is multiplication by x in GF(2^k).
+// Otherwise, this will panic.
+func dammOperation(n int) func(a, b int) int {
+	switch {
+	case n == 10:
+		return func(a, b int) int { return int(dammTable10[a][b]) }
+	case n%2 == 1:
+		return func(a, b int) int { return (2*a + b) % n }
+	case n > 2 && n&(n-1) == 0:
+		k := bits.Len(uint(n)) - 1
+		poly := gf2IrreduciblePolynomials[k]
+		return func(a, b int) int {
+			a <<= 1
+			if a >= n {
+				a ^= poly
+			}
+			return a ^ b
+		}
+	}
+	panic("random: CheckDamm requires the number of available characters to be odd, 10, or a power of two greater than 2")
+}
+
+// dammTable10 is the totally anti-symmetric quasigroup of order 10 from Damm's thesis.
+var dammTable10 = [10][10]uint8{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// gf2IrreduciblePolynomials are irreducible polynomials of degree k over GF(2), indexed by k.
+var gf2IrreduciblePolynomials = [9]int{0, 0, 0x7, 0xB, 0x13, 0x25, 0x43, 0x89, 0x11D}
@@ -0,0 +1,133 @@
+package random_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestSecureFormattedCode(t *testing.T) {
+	t.Parallel()
+	format := random.CodeFormat{AvailableCharBytes: random.UnambiguousBytes, Length: 12, GroupSize: 4, Separator: "-"}
+	for i := 0; i < 100; i++ {
+		code := random.SecureFormattedCode(format)
+		if len(code) != 14 || code[4] != '-' || code[9] != '-' {
+			t.Errorf("Expecting format XXXX-XXXX-XXXX; Got: %s", code)
+		}
+		if !random.VerifyCode(format, code) {
+			t.Errorf("Expecting code %s to verify", code)
+		}
+	}
+}
+
+func TestPseudoFormattedCodeRand(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	alphabets := [][]byte{random.HexBytes, random.Crockford32Bytes, random.UnambiguousBytes,
+		random.UnambiguousNoVowelsBytes, random.AlphaNumericBytes, []byte("0123456789")}
+	for _, check := range []random.CheckCharacter{random.CheckNone, random.CheckLuhn, random.CheckDamm} {
+		for _, alphabet := range alphabets {
+			if check == random.CheckDamm && len(alphabet) == 62 {
+				continue // Damm does not support this number of characters
+			}
+			format := random.CodeFormat{AvailableCharBytes: alphabet, Length: 10, GroupSize: 3, Separator: " ", Check: check}
+			for i := 0; i < 100; i++ {
+				code := random.PseudoFormattedCodeRand(source, format)
+				if len(strings.ReplaceAll(code, " ", "")) != 10+min(int(check), 1) {
+					t.Errorf("Unexpected length for code %q", code)
+				}
+				if !random.VerifyCode(format, code) {
+					t.Errorf("Expecting code %q to verify", code)
+				}
+			}
+		}
+	}
+}
+
+func TestFormattedCodeBlocklist(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	format := random.CodeFormat{AvailableCharBytes: []byte("AB"), Length: 3, Check: random.CheckLuhn, Blocklist: random.NewBlocklist("aa")}
+	for i := 0; i < 100; i++ {
+		if code := random.PseudoFormattedCodeRand(source, format); strings.Contains(code, "AA") || !random.VerifyCode(format, code) {
+			t.Errorf("Expecting a valid code without AA; Got: %q", code)
+		}
+	}
+
+	// Every random character is allowed, but the check character always makes a blocked pair
+	defer func() {
+		if recover() == nil {
+			t.Error("Expecting panic when every code is blocked")
+		}
+	}()
+	format = random.CodeFormat{AvailableCharBytes: []byte("AB"), Length: 1, Check: random.CheckLuhn, Blocklist: random.NewBlocklist("aa", "bb")}
+	random.PseudoFormattedCodeRand(source, format)
+}
+
+func TestVerifyCodeDetectsErrors(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	for _, alphabet := range [][]byte{random.Crockford32Bytes, random.UnambiguousBytes, []byte("0123456789")} {
+		format := random.CodeFormat{AvailableCharBytes: alphabet, Length: 8, Check: random.CheckDamm}
+		for i := 0; i < 50; i++ {
+			code := []byte(random.PseudoFormattedCodeRand(source, format))
+			for pos := range code {
+				// Every single character substitution must be detected
+				for _, c := range alphabet {
+					if c == code[pos] {
+						continue
+					}
+					typo := append([]byte{}, code...)
+					typo[pos] = c
+					if random.VerifyCode(format, string(typo)) {
+						t.Errorf("Expecting substitution %s of %s to fail verification", typo, code)
+					}
+				}
+				// Every adjacent transposition must be detected
+				if pos > 0 && code[pos] != code[pos-1] {
+					typo := append([]byte{}, code...)
+					typo[pos], typo[pos-1] = typo[pos-1], typo[pos]
+					if random.VerifyCode(format, string(typo)) {
+						t.Errorf("Expecting transposition %s of %s to fail verification", typo, code)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestVerifyCodeKnownValues(t *testing.T) {
+	t.Parallel()
+	digits := []byte("0123456789")
+	if !random.VerifyCode(random.CodeFormat{AvailableCharBytes: digits, Length: 3, Check: random.CheckDamm}, "5724") {
+		t.Error("Expecting Damm check digit of 572 to be 4")
+	}
+	if !random.VerifyCode(random.CodeFormat{AvailableCharBytes: digits, Length: 15, Check: random.CheckLuhn}, "4111111111111111") {
+		t.Error("Expecting Luhn check digit of 411111111111111 to be 1")
+	}
+}
+
+func TestNormalizeCode(t *testing.T) {
+	t.Parallel()
+	format := random.CodeFormat{AvailableCharBytes: random.Crockford32Bytes, Length: 8, GroupSize: 4, Separator: "-"}
+	tests := map[string]string{
+		"ABCD-EFGH":       "ABCD-EFGH",
+		"abcdefgh":        "ABCD-EFGH",
+		" ab cd-ef gh ":   "ABCD-EFGH",
+		"oOiI-lL01":       "0011-1101",
+		"A-B-C-D-E-F-G-H": "ABCD-EFGH",
+	}
+	for input, expected := range tests {
+		result, ok := random.NormalizeCode(format, input)
+		if !ok || result != expected {
+			t.Errorf("Expecting %q to normalize to %q; Got: %q %t", input, expected, result, ok)
+		}
+	}
+	for _, input := range []string{"ABCD-EFG", "ABCD-EFGHJ", "ABCD-EFGU", "ABCD_EFGH"} {
+		if result, ok := random.NormalizeCode(format, input); ok {
+			t.Errorf("Expecting %q to not normalize; Got: %q", input, result)
+		}
+	}
+}
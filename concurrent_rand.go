@@ -0,0 +1,53 @@
+package random
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"sync"
+)
+
+// ConcurrentRandSource uses math/rand, is thread-safe without locking, and implements math/rand.Source64.
+// It keeps a pool of generators, each seeded from crypto/rand, so that concurrent callers
+// on different processors do not contend on a single lock like the global math/rand instance does.
+// To use, call rand.New(random.ConcurrentRandSource) to get a *math/rand.Rand,
+// which, when only its methods that do not keep state (all but Read) are used, can be shared between goroutines.
+// Since Go 1.20 the global math/rand instance no longer locks unless rand.Seed is called,
+// so this is mainly useful for sharing a *math/rand.Rand with the *Rand functions.
+// Each pooled generator is about 4.9KB, and as sync.Pool drops its contents on garbage collection,
+// the generators are created and seeded from crypto/rand again after every GC, which makes the
+// first calls after a GC slower.
+// Not cryptographically secure. The sequence of values is not reproducible, and Seed does nothing.
+var ConcurrentRandSource rand.Source64 = concurrentRandSource{}
+
+// concurrentRandSourcePool holds math/rand sources, which are not thread-safe on their own.
+// sync.Pool keeps per-processor caches, so Get and Put do not lock in the common case.
+var concurrentRandSourcePool = sync.Pool{
+	New: func() any {
+		seed := int64(binary.LittleEndian.Uint64(SecureRandomBytes(8)))
+		return rand.NewSource(seed).(rand.Source64)
+	},
+}
+
+// concurrentRandSource is an empty struct that implements math/rand.Source64
+type concurrentRandSource struct{}
+
+// Uint64 allows implementation of math/rand.Source64
+func (s concurrentRandSource) Uint64() uint64 {
+	source := concurrentRandSourcePool.Get().(rand.Source64)
+	v := source.Uint64()
+	concurrentRandSourcePool.Put(source)
+	return v
+}
+
+// Int63 allows implementation of math/rand.Source
+func (s concurrentRandSource) Int63() int64 {
+	source := concurrentRandSourcePool.Get().(rand.Source64)
+	v := source.Int63()
+	concurrentRandSourcePool.Put(source)
+	return v
+}
+
+// Seed allows implementation of math/rand.Source
+func (s concurrentRandSource) Seed(seed int64) {
+	// no-op, as each pooled source is seeded from crypto/rand
+}
@@ -0,0 +1,46 @@
+package random_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/veqryn/go-random"
+)
+
+func BenchmarkConcurrentRandSourceParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			random.ConcurrentRandSource.Uint64()
+		}
+	})
+}
+
+func BenchmarkGlobalRandParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			rand.Uint64()
+		}
+	})
+}
+
+func BenchmarkConcurrentRandSourceStringBytesBase64Parallel(b *testing.B) {
+	b.ReportAllocs()
+	source := rand.New(random.ConcurrentRandSource)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			random.PseudoRandomStringBytesRand(source, benchmarkLength, random.Base64URLBytes)
+		}
+	})
+}
+
+func BenchmarkGlobalRandStringBytesBase64Parallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			random.PseudoRandomStringBytes(benchmarkLength, random.Base64URLBytes)
+		}
+	})
+}
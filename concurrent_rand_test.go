@@ -0,0 +1,46 @@
+package random_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/veqryn/go-random"
+)
+
+func TestConcurrentRandSource(t *testing.T) {
+	t.Parallel()
+	source := rand.New(random.ConcurrentRandSource)
+	var wg sync.WaitGroup
+	results := make([]map[uint64]bool, 8)
+	for g := range results {
+		results[g] = map[uint64]bool{}
+		wg.Add(1)
+		go func(seen map[uint64]bool) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				seen[random.ConcurrentRandSource.Uint64()] = true
+				if n := source.Int63n(10); n < 0 || n >= 10 {
+					t.Errorf("Expecting number in [0, 10); Got: %d", n)
+				}
+				if random.ConcurrentRandSource.Int63() < 0 {
+					t.Error("Expecting non-negative Int63")
+				}
+			}
+		}(results[g])
+	}
+	wg.Wait()
+	for _, seen := range results {
+		if len(seen) < 990 {
+			t.Errorf("Expecting nearly all values to be distinct; Got: %d", len(seen))
+		}
+	}
+
+	// Seed does nothing, so seeding again does not repeat the sequence
+	random.ConcurrentRandSource.Seed(1)
+	first := random.ConcurrentRandSource.Uint64()
+	random.ConcurrentRandSource.Seed(1)
+	if second := random.ConcurrentRandSource.Uint64(); first == second {
+		t.Errorf("Expecting Seed to not make the sequence reproducible; Got: %d twice", first)
+	}
+}
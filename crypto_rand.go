@@ -21,10 +21,10 @@ type secureRandSource struct{}
 // Uint64 allows implementation of math/rand.Source64
 func (s secureRandSource) Uint64() uint64 {
 	b := make([]byte, 8)
-	if _, err := rand.Read(b); err != nil {
-		panic("random: " + err.Error()) // Impossible
-	}
-	return binary.LittleEndian.Uint64(b)
+	secureRead(b)
+	v := binary.LittleEndian.Uint64(b)
+	clear(b)
+	return v
 }
 
 // Uint63 allows implementation of math/rand.Source
@@ -120,6 +120,7 @@ func secureRandomStringBytesSimple(length int, availableCharBytes []byte, bitsNe
 		// Put the byte at this index into the result
 		result[attempted] = availableCharBytes[charIdx]
 	}
+	clear(randomBytes)
 	return string(result)
 }
 
@@ -142,6 +143,9 @@ func secureRandomStringBytesComplex(length, availableCharLength int, availableCh
 
 	// Create the random string
 	for overflowMultiplier := maskOverflowMultiplier; ; overflowMultiplier += 1.0 {
+		if overflowMultiplier > maskOverflowMultiplier {
+			secureRetry()
+		}
 
 		// bitBufferSize is the length still needed multiplied by bits needed per character.
 		// When the bitMask can potentially overflow the available character options,
@@ -173,10 +177,12 @@ func secureRandomStringBytesComplex(length, availableCharLength int, availableCh
 				result[completed] = availableCharBytes[charIdx]
 				completed++
 				if completed == length {
+					clear(randomBits)
 					return string(result)
 				}
 			}
 		}
+		clear(randomBits)
 	}
 }
 
@@ -225,6 +231,9 @@ func SecureRandomStringRunes(length int, availableCharRunes []rune) string {
 
 	// Create the random string
 	for overflowMultiplier := maskOverflowMultiplier; ; overflowMultiplier += 1.0 {
+		if overflowMultiplier > maskOverflowMultiplier {
+			secureRetry()
+		}
 
 		// bitBufferSize is the length still needed multiplied by bits needed per character.
 		// When the bitMask can potentially overflow the available character options,
@@ -256,10 +265,12 @@ func SecureRandomStringRunes(length int, availableCharRunes []rune) string {
 				result[completed] = availableCharRunes[charIdx]
 				completed++
 				if completed == length {
+					clear(randomBits)
 					return string(result)
 				}
 			}
 		}
+		clear(randomBits)
 	}
 }
 
@@ -336,15 +347,14 @@ func SecureRandomBitBlocks(bitLength, usableBlockSize int, order binary.ByteOrde
 	randomBits := make([]uint64, uint64Length)
 
 	// Read only the portion of random data that is needed, not the full slice
-	if _, err := rand.Read(randomBytes[:byteLength]); err != nil {
-		panic("random: " + err.Error()) // Impossible
-	}
+	secureRead(randomBytes[:byteLength])
 
 	// Set the bits using ByteOrder
 	// If `64 % usableBlockSize >= 8` it is possible but not practical to use fewer than 8 bytes per uint64
 	for i := range randomBits {
 		randomBits[i] = order.Uint64(randomBytes[8*i:])
 	}
+	clear(randomBytes)
 
 	// Return randomBits and the number of usable bit blocks it contains
 	return randomBits, indicesPerUint64*fullIndexByteCount + (8 * remainderByteCount / usableBlockSize)
@@ -355,22 +365,22 @@ func SecureRandomHex(length int) string {
 	// Each byte has 2 hex values in it, so round length up and grab random data
 	randomBytes := SecureRandomBytes(int(math.Ceil(float64(length) / 2.0)))
 	// Encode to hex and cut off the last hex if an odd length was requested
-	return hex.EncodeToString(randomBytes)[:length]
+	result := hex.EncodeToString(randomBytes)[:length]
+	clear(randomBytes)
+	return result
 }
 
 // SecureRandomBytes uses crypto/rand to return a slice of random byte data of a given length
 func SecureRandomBytes(length int) []byte {
 	randomBytes := make([]byte, length)
-	if _, err := rand.Read(randomBytes); err != nil {
-		panic("random: " + err.Error()) // Impossible
-	}
+	secureRead(randomBytes)
 	return randomBytes
 }
 
 // SecureRandomNumber uses crypto/rand to return a number between [minInclusive, maxExclusive)
 func SecureRandomNumber(minInclusive int64, maxExclusive int64) int64 {
 	min := big.NewInt(minInclusive)
-	r, err := rand.Int(rand.Reader, big.NewInt(0).Sub(big.NewInt(maxExclusive), min))
+	r, err := rand.Int(secureReader{}, big.NewInt(0).Sub(big.NewInt(maxExclusive), min))
 	if err != nil {
 		panic("random: " + err.Error()) // Impossible
 	}
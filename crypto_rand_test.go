@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
 )
 
 func TestSecureRandomString(t *testing.T) {
@@ -111,7 +112,7 @@ func TestSecureRandomBytes(t *testing.T) {
 func TestSecureRandomNumber(t *testing.T) {
 	t.Parallel()
 	increment := int64(math.MaxInt64 / 200)
-	source := math_rand.New(math_rand.NewSource(random.SecureRandomNumber(math.MinInt64, math.MaxInt64)))
+	source := randtest.Rand(t)
 	for min := int64(math.MinInt64); min < math.MaxInt64-increment; min += increment {
 		max := random.PseudoRandomInt63Rand(source, 0, math.MaxInt64) - random.PseudoRandomInt63Rand(source, 0, math.MaxInt64)
 		if max <= min {
@@ -0,0 +1,90 @@
+package random
+
+import (
+	"crypto/sha3"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Lengths of CUID2 IDs.
+const (
+	Cuid2MinLength     = 2
+	Cuid2DefaultLength = 24
+	Cuid2MaxLength     = 32
+)
+
+// cuid2Base36Bytes are the characters of CUID2 IDs, after the first letter.
+var cuid2Base36Bytes = []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+
+// cuid2LetterBytes are the characters the first letter of a CUID2 ID is chosen from.
+var cuid2LetterBytes = cuid2Base36Bytes[10:]
+
+// cuid2InitialCountMax is the exclusive maximum of the random starting value of the counter.
+const cuid2InitialCountMax = 476782367
+
+// Cuid2Generator creates CUID2 IDs, which are collision-resistant IDs that are safe to show to users,
+// as they do not reveal when or where they were created: a random lower case letter, followed by base36
+// characters from a SHA3-512 hash of the time, crypto/rand entropy, a counter, and a fingerprint of the host.
+// IDs match the format and collision characteristics of github.com/paralleldrive/cuid2.
+// It is thread-safe.
+type Cuid2Generator struct {
+	length      int
+	fingerprint string
+	counter     atomic.Uint64
+}
+
+// NewCuid2Generator returns a Cuid2Generator for IDs of the given length.
+// If length is not between Cuid2MinLength and Cuid2MaxLength, this will panic.
+func NewCuid2Generator(length int) *Cuid2Generator {
+	if length < Cuid2MinLength || length > Cuid2MaxLength {
+		panic("random: cuid2 length must be between 2 and 32")
+	}
+	hostname, _ := os.Hostname()
+	g := &Cuid2Generator{
+		length:      length,
+		fingerprint: cuid2Hash(hostname + strconv.Itoa(os.Getpid()) + SecureRandomStringBytes(Cuid2MaxLength, cuid2Base36Bytes))[:Cuid2MaxLength],
+	}
+	g.counter.Store(uint64(secureInt63n(cuid2InitialCountMax)))
+	return g
+}
+
+// defaultCuid2Generator is used by NewCuid2. It is created when first used,
+// so that importing this package does not read the hostname or crypto/rand.
+var defaultCuid2Generator = sync.OnceValue(func() *Cuid2Generator {
+	return NewCuid2Generator(Cuid2DefaultLength)
+})
+
+// NewCuid2 returns a new CUID2 ID of the default length of 24.
+func NewCuid2() string {
+	return defaultCuid2Generator().Generate()
+}
+
+// Generate returns a new CUID2 ID.
+func (g *Cuid2Generator) Generate() string {
+	firstLetter := SecureRandomStringBytes(1, cuid2LetterBytes)
+	now := strconv.FormatInt(time.Now().UnixMilli(), 36)
+	entropy := SecureRandomStringBytes(g.length, cuid2Base36Bytes)
+	count := strconv.FormatUint(g.counter.Add(1)-1, 36)
+	return firstLetter + cuid2Hash(now + entropy + count + g.fingerprint)[1:g.length]
+}
+
+// cuid2Hash returns the SHA3-512 hash of the input as a base36 number, without its first character,
+// which is biased towards lower values.
+func cuid2Hash(input string) string {
+	sum := sha3.Sum512([]byte(input))
+	return new(big.Int).SetBytes(sum[:]).Text(36)[1:]
+}
+
+// IsCuid returns true if the id could be a CUID2 ID: between Cuid2MinLength and Cuid2MaxLength
+// lower case letters and digits. Like cuid2's isCuid, it does not require the first character to be a letter.
+func IsCuid(id string) bool {
+	if len(id) < Cuid2MinLength || len(id) > Cuid2MaxLength {
+		return false
+	}
+	return strings.Trim(id, string(cuid2Base36Bytes)) == ""
+}
@@ -0,0 +1,54 @@
+package random_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/veqryn/go-random"
+)
+
+func TestNewCuid2(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2500; i++ {
+				id := random.NewCuid2()
+				if len(id) != random.Cuid2DefaultLength || id[0] < 'a' || id[0] > 'z' || !random.IsCuid(id) {
+					t.Errorf("Expecting a 24 character CUID2 starting with a letter; Got: %s", id)
+				}
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("Expecting no collisions; Got: %s", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for length := random.Cuid2MinLength; length <= random.Cuid2MaxLength; length++ {
+		if id := random.NewCuid2Generator(length).Generate(); len(id) != length || !random.IsCuid(id) {
+			t.Errorf("Expecting CUID2 of length %d; Got: %s", length, id)
+		}
+	}
+}
+
+func TestIsCuid(t *testing.T) {
+	t.Parallel()
+	for _, valid := range []string{"tz4a98xxat96iws9zmbrgj3a", "a1", "0abc"} {
+		if !random.IsCuid(valid) {
+			t.Errorf("Expecting %q to be valid", valid)
+		}
+	}
+	for _, invalid := range []string{"", "a", "Tz4a98xxat96iws9zmbrgj3a", "tz4a98xx-t96iws9zmbrgj3a", "tz4a98xxat96iws9zmbrgj3atz4a98xxa"} {
+		if random.IsCuid(invalid) {
+			t.Errorf("Expecting %q to be invalid", invalid)
+		}
+	}
+}
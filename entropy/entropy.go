@@ -0,0 +1,341 @@
+// Package entropy estimates how much entropy a sample of bytes contains, using the
+// min-entropy estimators of NIST SP 800-90B section 6.3 and the Shannon entropy.
+//
+// It is a sanity check, such as for the output of the random package or for tokens and
+// secrets supplied by someone else, rather than an entropy source validation: the estimators
+// need large samples, ideally a million bytes or more, and can only detect the kinds of
+// predictability they look for. Output that fails is definitely not random, but output that
+// passes may still be predictable, such as the output of a seeded PRNG.
+//
+//	sample := []byte(strings.Join(tokens, ""))
+//	estimates, err := entropy.Estimate(sample)
+//	if err == nil && estimates.MinEntropy() < 5 {
+//		// The tokens have less than 5 bits of entropy per byte
+//	}
+//
+// Every estimate is in bits per byte, from 0 for completely predictable data to 8 for
+// data indistinguishable from uniformly random bytes.
+package entropy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// MinSampleSize is the fewest bytes Estimate and Compression accept.
+// The estimates are more accurate with larger samples.
+const MinSampleSize = 1024
+
+// ErrNotEnoughData is returned by Estimate and EstimateReader when the sample is shorter than MinSampleSize.
+var ErrNotEnoughData = errors.New("entropy: sample must be at least 1024 bytes")
+
+// zAlpha is the 99.5th percentile of the standard normal distribution, used by SP 800-90B
+// to turn each estimate into a conservative upper bound of the probability.
+const zAlpha = 2.576
+
+// Estimates are the entropy estimates of a sample, in bits per byte.
+type Estimates struct {
+	// Shannon is the Shannon entropy of the byte frequencies. It is not a min-entropy estimate, so
+	// is not used by MinEntropy, and it is not affected by the order of the bytes.
+	Shannon float64
+
+	// MostCommonValue is the SP 800-90B 6.3.1 most common value estimate of the bytes.
+	MostCommonValue float64
+
+	// Collision is the SP 800-90B 6.3.2 collision estimate of the bits.
+	Collision float64
+
+	// Markov is the SP 800-90B 6.3.3 Markov estimate of the bits.
+	Markov float64
+
+	// Compression is the SP 800-90B 6.3.4 compression estimate of the bits.
+	Compression float64
+}
+
+// MinEntropy returns the lowest of the min-entropy estimates, which is the conservative
+// estimate of the sample's min-entropy per byte.
+func (e Estimates) MinEntropy() float64 {
+	return min(e.MostCommonValue, e.Collision, e.Markov, e.Compression)
+}
+
+// String returns the estimates in a human readable form.
+func (e Estimates) String() string {
+	return fmt.Sprintf("min-entropy %.4f bits/byte (most common value %.4f, collision %.4f, markov %.4f, compression %.4f), shannon %.4f bits/byte",
+		e.MinEntropy(), e.MostCommonValue, e.Collision, e.Markov, e.Compression, e.Shannon)
+}
+
+// Estimate returns all entropy estimates of the sample.
+// It returns ErrNotEnoughData if the sample is shorter than MinSampleSize.
+func Estimate(data []byte) (Estimates, error) {
+	if len(data) < MinSampleSize {
+		return Estimates{}, ErrNotEnoughData
+	}
+	return Estimates{
+		Shannon:         Shannon(data),
+		MostCommonValue: MostCommonValue(data),
+		Collision:       Collision(data),
+		Markov:          Markov(data),
+		Compression:     Compression(data),
+	}, nil
+}
+
+// EstimateReader reads a sample of the given size from the reader, then returns its entropy estimates.
+// It returns ErrNotEnoughData if the size is less than MinSampleSize, or the reader's error
+// if it can not supply that many bytes.
+func EstimateReader(r io.Reader, size int) (Estimates, error) {
+	if size < MinSampleSize {
+		return Estimates{}, ErrNotEnoughData
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return Estimates{}, err
+	}
+	return Estimate(data)
+}
+
+// Shannon returns the Shannon entropy of the byte frequencies of the data, in bits per byte.
+// If the data is empty, this will panic.
+func Shannon(data []byte) float64 {
+	if len(data) == 0 {
+		panic("entropy: data must not be empty")
+	}
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+	h := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(len(data))
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
+
+// MostCommonValue returns the most common value estimate of the min-entropy of the bytes
+// of the data (SP 800-90B 6.3.1), in bits per byte, based on an upper bound of the
+// probability of the most common byte.
+// If the data has fewer than 2 bytes, this will panic.
+func MostCommonValue(data []byte) float64 {
+	if len(data) < 2 {
+		panic("entropy: data must have at least 2 bytes")
+	}
+	var counts [256]int
+	mode := 0
+	for _, b := range data {
+		counts[b]++
+		mode = max(mode, counts[b])
+	}
+	length := float64(len(data))
+	p := float64(mode) / length
+	upper := min(1, p+zAlpha*math.Sqrt(p*(1-p)/(length-1)))
+	return math.Log2(1 / upper)
+}
+
+// bits returns the bits of the data, most significant first, as 0 or 1.
+func bits(data []byte) []byte {
+	s := make([]byte, 0, len(data)*8)
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			s = append(s, b>>i&1)
+		}
+	}
+	return s
+}
+
+// Collision returns the collision estimate of the min-entropy of the bits of the data
+// (SP 800-90B 6.3.2), times 8 to give bits per byte, based on the mean number of bits
+// read before a bit repeats.
+// If the data has fewer than 2 bytes, this will panic.
+func Collision(data []byte) float64 {
+	if len(data) < 2 {
+		panic("entropy: data must have at least 2 bytes")
+	}
+	s := bits(data)
+
+	// For bits, a collision is found after 2 bits if the first two are equal, otherwise after 3
+	var times []float64
+	for i := 0; i+1 < len(s); {
+		if s[i] == s[i+1] {
+			times = append(times, 2)
+			i += 2
+		} else if i+2 < len(s) {
+			times = append(times, 3)
+			i += 3
+		} else {
+			break
+		}
+	}
+
+	mean, stddev := meanStddev(times)
+	lower := mean - zAlpha*stddev/math.Sqrt(float64(len(times)))
+
+	// The expected time is 2 + 2pq, where p is the probability of the more likely bit and q = 1 - p
+	p := 0.5
+	if pq := (lower - 2) / 2; pq < 0 {
+		p = 1
+	} else if pq < 0.25 {
+		p = 0.5 + math.Sqrt(0.25-pq)
+	}
+	return math.Log2(1/p) * 8
+}
+
+// meanStddev returns the mean and sample standard deviation of the values.
+func meanStddev(values []float64) (mean, stddev float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		stddev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stddev / float64(len(values)-1))
+}
+
+// Markov returns the Markov estimate of the min-entropy of the bits of the data
+// (SP 800-90B 6.3.3), times 8 to give bits per byte, based on the most likely
+// 128 bit sequence when each bit depends on the bit before it.
+// If the data has fewer than 2 bytes, this will panic.
+func Markov(data []byte) float64 {
+	if len(data) < 2 {
+		panic("entropy: data must have at least 2 bytes")
+	}
+	s := bits(data)
+
+	var ones int
+	var transitions [2][2]int
+	for i, bit := range s {
+		ones += int(bit)
+		if i > 0 {
+			transitions[s[i-1]][bit]++
+		}
+	}
+	p1 := float64(ones) / float64(len(s))
+	p0 := 1 - p1
+
+	// Transition probabilities, where p[a][b] is the chance that bit b follows bit a
+	var p [2][2]float64
+	for a := range transitions {
+		if total := transitions[a][0] + transitions[a][1]; total > 0 {
+			p[a][0] = float64(transitions[a][0]) / float64(total)
+			p[a][1] = float64(transitions[a][1]) / float64(total)
+		}
+	}
+
+	// The most likely sequence of 128 bits is one of these six
+	pMax := max(
+		p0*math.Pow(p[0][0], 127),                      // 000...0
+		p0*math.Pow(p[0][1], 64)*math.Pow(p[1][0], 63), // 0101...01
+		p0*p[0][1]*math.Pow(p[1][1], 126),              // 011...1
+		p1*p[1][0]*math.Pow(p[0][0], 126),              // 100...0
+		p1*math.Pow(p[1][0], 64)*math.Pow(p[0][1], 63), // 1010...10
+		p1*math.Pow(p[1][1], 127),                      // 111...1
+	)
+	return min(math.Log2(1/pMax)/128, 1) * 8
+}
+
+// Compression parameters from SP 800-90B 6.3.4: bits per block, and blocks used to initialize the dictionary.
+const (
+	compressionBlockBits = 6
+	compressionDictSize  = 1000
+)
+
+// Compression returns the compression estimate of the min-entropy of the bits of the data
+// (SP 800-90B 6.3.4), times 8 to give bits per byte, based on how far back each 6 bit block
+// was last seen, as a dictionary compressor would.
+// It is the most conservative of the estimators, giving around 6.5 bits per byte for uniformly random data.
+// It takes time proportional to the length of the data.
+// If the data is shorter than MinSampleSize, this will panic.
+func Compression(data []byte) float64 {
+	if len(data) < MinSampleSize {
+		panic("entropy: data must have at least 1024 bytes")
+	}
+	s := bits(data)
+
+	// Split into blocks, and record the distance to each block's last occurrence
+	blocks := len(s) / compressionBlockBits
+	var lastSeen [1 << compressionBlockBits]int
+	var distances []float64
+	for i := 1; i <= blocks; i++ {
+		block := 0
+		for _, bit := range s[(i-1)*compressionBlockBits : i*compressionBlockBits] {
+			block = block<<1 | int(bit)
+		}
+		if i > compressionDictSize {
+			if lastSeen[block] != 0 {
+				distances = append(distances, math.Log2(float64(i-lastSeen[block])))
+			} else {
+				distances = append(distances, math.Log2(float64(i)))
+			}
+		}
+		lastSeen[block] = i
+	}
+
+	mean, _ := meanStddev(distances)
+	sumSquares := 0.0
+	for _, d := range distances {
+		sumSquares += d * d
+	}
+	v := float64(len(distances))
+	stddev := 0.5907 * math.Sqrt(max(0, sumSquares/(v-1)-mean*mean))
+	lower := mean - zAlpha*stddev/math.Sqrt(v)
+
+	// Find the probability p of the most likely block whose expected mean is the lower bound,
+	// where every other block is equally likely. The expected mean falls as p rises, to 0 when p is 1,
+	// which is the case for constant data.
+	if lower <= 0 {
+		return 0
+	}
+	logs := make([]float64, blocks+1)
+	for t := 1; t <= blocks; t++ {
+		logs[t] = math.Log2(float64(t))
+	}
+	const others = 1<<compressionBlockBits - 1
+	expected := func(p float64) float64 {
+		return compressionG(p, logs) + others*compressionG((1-p)/others, logs)
+	}
+	lo, hi := 1.0/(1<<compressionBlockBits), 1.0
+	if lower >= expected(lo) {
+		return 8
+	}
+	// Stop once p can not be narrowed further, which takes fewer iterations than 64 as p nears 1.
+	// The iterations near 1 are the slowest, as G of the other blocks sums over every block.
+	for mid := (lo + hi) / 2; mid > lo && mid < hi; mid = (lo + hi) / 2 {
+		if expected(mid) > lower {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return math.Log2(1/hi) / compressionBlockBits * 8
+}
+
+// compressionG is the function G(z) of SP 800-90B 6.3.4: the expected log2 of the distance
+// back to a block with probability z, averaged over the tested blocks, where logs[t] is log2(t)
+// and there is one entry for each block.
+func compressionG(z float64, logs []float64) float64 {
+	if z <= 0 || z >= 1 {
+		return 0
+	}
+	// sum of log2(u) z^2 (1-z)^(u-1) for u < t, built up as t increases
+	blocks := len(logs) - 1
+	q := 1 - z
+	sumBefore, total := 0.0, 0.0
+	qPow := 1.0 // (1-z)^(t-2)
+	for t := 2; t <= blocks; t++ {
+		if qPow < 1e-30 {
+			// The remaining terms no longer change the sum, so add them all at once
+			total += sumBefore * float64(blocks-max(t, compressionDictSize+1)+1)
+			break
+		}
+		sumBefore += logs[t-1] * z * z * qPow
+		qPow *= q
+		if t > compressionDictSize {
+			total += sumBefore + logs[t]*z*qPow
+		}
+	}
+	return total / float64(blocks-compressionDictSize)
+}
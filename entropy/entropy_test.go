@@ -0,0 +1,144 @@
+package entropy_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/entropy"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestEstimateRandom(t *testing.T) {
+	t.Parallel()
+	data := make([]byte, 100_000)
+	randtest.Rand(t).Read(data)
+
+	estimates, err := entropy.Estimate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct{ got, min float64 }{
+		"shannon":           {estimates.Shannon, 7.99},
+		"most common value": {estimates.MostCommonValue, 7.4},
+		"collision":         {estimates.Collision, 6.5},
+		"markov":            {estimates.Markov, 7.9},
+		"compression":       {estimates.Compression, 6},
+	} {
+		if tc.got < tc.min || tc.got > 8 {
+			t.Errorf("Expecting %s estimate between %v and 8; Got: %v", name, tc.min, tc.got)
+		}
+	}
+	if estimates.MinEntropy() != min(estimates.MostCommonValue, estimates.Collision, estimates.Markov, estimates.Compression) {
+		t.Errorf("Expecting the lowest estimate; Got: %v", estimates)
+	}
+}
+
+func TestEstimateBiasedBits(t *testing.T) {
+	t.Parallel()
+	// Each bit is 1 with probability 0.8, for a true min-entropy of 8 * -log2(0.8) bits per byte
+	r := randtest.Rand(t)
+	data := make([]byte, 100_000)
+	for i := range data {
+		for j := 0; j < 8; j++ {
+			data[i] <<= 1
+			if r.Float64() < 0.8 {
+				data[i] |= 1
+			}
+		}
+	}
+	expected := -8 * math.Log2(0.8)
+
+	estimates, err := entropy.Estimate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, got := range map[string]float64{
+		"collision": estimates.Collision,
+		"markov":    estimates.Markov,
+	} {
+		if math.Abs(got-expected) > 0.1 {
+			t.Errorf("Expecting %s estimate near %v; Got: %v", name, expected, got)
+		}
+	}
+	if estimates.MinEntropy() > expected {
+		t.Errorf("Expecting min-entropy at most %v; Got: %v", expected, estimates)
+	}
+}
+
+func TestEstimatePredictable(t *testing.T) {
+	t.Parallel()
+	estimates, err := entropy.Estimate(make([]byte, 10_000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimates != (entropy.Estimates{}) {
+		t.Errorf("Expecting all zero estimates for constant data; Got: %v", estimates)
+	}
+
+	// A repeating pattern has a uniform-looking bit distribution, but the bytes repeat
+	estimates, err = entropy.Estimate(bytes.Repeat([]byte("abcd"), 2500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimates.Shannon != 2 || estimates.MinEntropy() > 2 {
+		t.Errorf("Expecting at most 2 bits per byte; Got: %v", estimates)
+	}
+}
+
+func TestCompressionLarge(t *testing.T) {
+	// Not parallel, as it compares timings.
+	// Nearly constant data is the slowest to estimate, but should take about as long as random data.
+	if testing.Short() {
+		t.Skip("Skipping large sample in short mode")
+	}
+	r := randtest.Rand(t)
+	randomData := make([]byte, 1<<20)
+	r.Read(randomData)
+	constantData := make([]byte, 1<<20)
+	for i := 0; i < 100; i++ {
+		constantData[r.Intn(len(constantData))] = byte(r.Intn(256))
+	}
+
+	start := time.Now()
+	if got := entropy.Compression(randomData); got < 6 {
+		t.Errorf("Expecting compression estimate of at least 6 for random data; Got: %v", got)
+	}
+	randomTime := time.Since(start)
+
+	start = time.Now()
+	if got := entropy.Compression(constantData); got > 0.01 {
+		t.Errorf("Expecting compression estimate near 0 for nearly constant data; Got: %v", got)
+	}
+	if constantTime := time.Since(start); constantTime > 10*randomTime {
+		t.Errorf("Expecting nearly constant data to take about as long as random data (%v); Got: %v", randomTime, constantTime)
+	}
+	if got := entropy.Compression(make([]byte, 1<<20)); got != 0 {
+		t.Errorf("Expecting compression estimate of 0 for constant data; Got: %v", got)
+	}
+}
+
+func TestEstimateHex(t *testing.T) {
+	t.Parallel()
+	// Hex characters carry 4 bits of entropy in each byte
+	estimates, err := entropy.EstimateReader(bytes.NewReader([]byte(random.SecureRandomHex(50_000))), 50_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimates.Shannon < 3.99 || estimates.Shannon > 4 || estimates.MostCommonValue > 4 {
+		t.Errorf("Expecting about 4 bits per byte; Got: %v", estimates)
+	}
+}
+
+func TestEstimateNotEnoughData(t *testing.T) {
+	t.Parallel()
+	if _, err := entropy.Estimate(make([]byte, entropy.MinSampleSize-1)); !errors.Is(err, entropy.ErrNotEnoughData) {
+		t.Errorf("Expecting ErrNotEnoughData; Got: %v", err)
+	}
+	if _, err := entropy.EstimateReader(bytes.NewReader(make([]byte, 10)), entropy.MinSampleSize); err == nil {
+		t.Errorf("Expecting an error from a short reader; Got: nil")
+	}
+}
@@ -0,0 +1,273 @@
+// Package fake generates realistic looking fake data, such as names, email addresses,
+// street addresses, phone numbers, company names and lorem ipsum, for test fixtures.
+// All data is generated with the Pseudo*Rand functions of the random package, so the
+// output is deterministic for a given seed and locale.
+// Not cryptographically secure.
+package fake
+
+import (
+	"embed"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/veqryn/go-random"
+)
+
+//go:embed locales/*.txt
+var localeFiles embed.FS
+
+// Locale is a dataset of names, places and formats for a language and region.
+type Locale struct {
+	// Name is the locale's identifier, such as "en_US".
+	Name string
+
+	lists map[string][]string
+}
+
+// Embedded locales.
+var (
+	EnUS = mustLoadLocale("en_US")
+	DeDE = mustLoadLocale("de_DE")
+)
+
+// mustLoadLocale parses the embedded locale file, plus the shared lorem ipsum words.
+// Each file is made of sections, started by a line like "[first_names]", followed by one entry per line.
+// Empty lines are ignored, as are lines starting with "#" before the first section
+// (within a section, "#" is a digit placeholder in formats).
+func mustLoadLocale(name string) *Locale {
+	locale := &Locale{Name: name, lists: map[string][]string{}}
+	for _, file := range []string{"locales/lorem.txt", "locales/" + name + ".txt"} {
+		data, err := localeFiles.ReadFile(file)
+		if err != nil {
+			panic("fake: " + err.Error()) // Impossible
+		}
+		section := ""
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			switch {
+			case line == "" || strings.HasPrefix(line, "#") && section == "":
+				continue
+			case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+				section = line[1 : len(line)-1]
+			default:
+				locale.lists[section] = append(locale.lists[section], line)
+			}
+		}
+	}
+	return locale
+}
+
+// Faker generates fake data from a locale. A Faker is not safe for concurrent use,
+// because the *rand.Rand it uses is not.
+type Faker struct {
+	rand   *rand.Rand
+	locale *Locale
+}
+
+// New returns a Faker that generates the same data every time for the same seed and locale.
+// If locale is nil, EnUS is used.
+func New(seed int64, locale *Locale) *Faker {
+	return NewRand(rand.New(rand.NewSource(seed)), locale)
+}
+
+// NewRand returns a Faker using the rand source.
+// If locale is nil, EnUS is used.
+func NewRand(rand *rand.Rand, locale *Locale) *Faker {
+	if locale == nil {
+		locale = EnUS
+	}
+	return &Faker{rand: rand, locale: locale}
+}
+
+// pick returns a random entry from the locale's list.
+func (f *Faker) pick(list string) string {
+	entries := f.locale.lists[list]
+	if len(entries) == 0 {
+		panic("fake: locale " + f.locale.Name + " has no " + list)
+	}
+	return entries[random.PseudoRandomInt63Rand(f.rand, 0, int64(len(entries)))]
+}
+
+// format expands a template from the locale. Placeholders like {city} are replaced
+// by the generator of the same name, placeholders like {01001..99998} by a random number
+// in that inclusive range, padded with zeros to the width of the first number,
+// and each "#" is replaced by a random digit.
+func (f *Faker) format(template string) string {
+	var sb strings.Builder
+	for len(template) > 0 {
+		switch {
+		case template[0] == '#':
+			sb.WriteString(random.PseudoRandomStringBytesRand(f.rand, 1, digitBytes))
+			template = template[1:]
+
+		case template[0] == '{':
+			end := strings.IndexByte(template, '}')
+			if end < 0 {
+				panic("fake: unterminated placeholder in " + template)
+			}
+			if lo, hi, isRange := strings.Cut(template[1:end], ".."); isRange {
+				sb.WriteString(f.numberBetween(lo, hi))
+			} else if generator, ok := placeholders[template[1:end]]; ok {
+				sb.WriteString(generator(f))
+			} else {
+				panic("fake: unknown placeholder " + template[:end+1])
+			}
+			template = template[end+1:]
+
+		default:
+			sb.WriteByte(template[0])
+			template = template[1:]
+		}
+	}
+	return sb.String()
+}
+
+var digitBytes = []byte("0123456789")
+
+// numberBetween returns a random number in the inclusive range of a {lo..hi} placeholder,
+// padded with zeros to the width of lo.
+func (f *Faker) numberBetween(lo, hi string) string {
+	minInclusive, err1 := strconv.ParseInt(lo, 10, 64)
+	maxInclusive, err2 := strconv.ParseInt(hi, 10, 64)
+	if err1 != nil || err2 != nil || minInclusive < 0 || maxInclusive < minInclusive {
+		panic("fake: invalid range placeholder {" + lo + ".." + hi + "}")
+	}
+	n := strconv.FormatInt(random.PseudoRandomInt63Rand(f.rand, minInclusive, maxInclusive+1), 10)
+	return strings.Repeat("0", len(lo)-len(n)) + n
+}
+
+// areaCode returns a random North American area code, which starts with 2 to 9,
+// and is not one of the N11 service codes, such as 411 and 911.
+func areaCode(f *Faker) string {
+	for {
+		if code := random.PseudoRandomInt63Rand(f.rand, 200, 1000); code%100 != 11 {
+			return strconv.FormatInt(code, 10)
+		}
+	}
+}
+
+// placeholders are the generators that locale templates can refer to.
+var placeholders map[string]func(*Faker) string
+
+func init() {
+	placeholders = map[string]func(*Faker) string{
+		"first_name":     (*Faker).FirstName,
+		"last_name":      (*Faker).LastName,
+		"street":         func(f *Faker) string { return f.pick("streets") },
+		"street_suffix":  func(f *Faker) string { return f.pick("street_suffixes") },
+		"street_address": (*Faker).StreetAddress,
+		"city":           (*Faker).City,
+		"region":         (*Faker).Region,
+		"postal_code":    (*Faker).PostalCode,
+		"area_code":      areaCode,
+		"company_word":   func(f *Faker) string { return f.pick("company_words") },
+	}
+}
+
+// FirstName returns a random first name.
+func (f *Faker) FirstName() string {
+	return f.pick("first_names")
+}
+
+// LastName returns a random last name.
+func (f *Faker) LastName() string {
+	return f.pick("last_names")
+}
+
+// Name returns a random full name.
+func (f *Faker) Name() string {
+	return f.FirstName() + " " + f.LastName()
+}
+
+// StreetAddress returns a random street address, such as "1234 Oak Avenue".
+func (f *Faker) StreetAddress() string {
+	return f.format(f.pick("street_formats"))
+}
+
+// City returns a random city.
+func (f *Faker) City() string {
+	return f.pick("cities")
+}
+
+// Region returns a random state, province or region.
+func (f *Faker) Region() string {
+	return f.pick("regions")
+}
+
+// PostalCode returns a random postal or zip code.
+func (f *Faker) PostalCode() string {
+	return f.format(f.pick("postal_code_formats"))
+}
+
+// Address returns a random single line postal address.
+func (f *Faker) Address() string {
+	return f.format(f.pick("address_formats"))
+}
+
+// Phone returns a random phone number from the ranges the locale reserves for fiction,
+// such as 555-0100 to 555-0199 in North America, and the Bundesnetzagentur's ranges for
+// film and television in Germany.
+func (f *Faker) Phone() string {
+	return f.format(f.pick("phone_formats"))
+}
+
+// Company returns a random company name.
+func (f *Faker) Company() string {
+	return f.format(f.pick("company_formats"))
+}
+
+// emailDomains are second level domains reserved for documentation by RFC 2606.
+var emailDomains = []string{"example.com", "example.net", "example.org"}
+
+// Email returns a random email address at a domain reserved for testing by RFC 2606,
+// either example.com, example.net, example.org, or a subdomain of the .test top level domain.
+func (f *Faker) Email() string {
+	local := emailPart(f.FirstName()) + "." + emailPart(f.LastName())
+	if f.rand.Intn(2) == 0 {
+		local += random.PseudoRandomStringBytesRand(f.rand, 2, digitBytes)
+	}
+	if f.rand.Intn(4) == 0 {
+		return local + "@" + emailPart(f.pick("company_words")) + ".test"
+	}
+	return local + "@" + emailDomains[f.rand.Intn(len(emailDomains))]
+}
+
+// emailTransliterations replace non-ascii letters commonly found in names.
+var emailTransliterations = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss", "é", "e", "è", "e", "á", "a", "ñ", "n")
+
+// emailPart returns s lower cased, transliterated to ascii, and with anything other than letters and digits removed.
+func emailPart(s string) string {
+	s = emailTransliterations.Replace(strings.ToLower(s))
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// Words returns count random lorem ipsum words separated by spaces.
+func (f *Faker) Words(count int) string {
+	words := make([]string, count)
+	for i := range words {
+		words[i] = f.pick("lorem_words")
+	}
+	return strings.Join(words, " ")
+}
+
+// Sentence returns a random lorem ipsum sentence of 4 to 12 words.
+func (f *Faker) Sentence() string {
+	words := f.Words(int(random.PseudoRandomInt63Rand(f.rand, 4, 13)))
+	return strings.ToUpper(words[:1]) + words[1:] + "."
+}
+
+// Paragraph returns a random lorem ipsum paragraph of 3 to 6 sentences.
+func (f *Faker) Paragraph() string {
+	sentences := make([]string, random.PseudoRandomInt63Rand(f.rand, 3, 7))
+	for i := range sentences {
+		sentences[i] = f.Sentence()
+	}
+	return strings.Join(sentences, " ")
+}
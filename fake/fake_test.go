@@ -0,0 +1,87 @@
+package fake_test
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/veqryn/go-random/fake"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestNewDeterministic(t *testing.T) {
+	t.Parallel()
+	seed := randtest.Seed(t)
+	for _, locale := range []*fake.Locale{fake.EnUS, fake.DeDE} {
+		first, second := fake.New(seed, locale), fake.New(seed, locale)
+		for i := 0; i < 100; i++ {
+			a := []string{first.Name(), first.Email(), first.Address(), first.Phone(), first.Company(), first.Paragraph()}
+			b := []string{second.Name(), second.Email(), second.Address(), second.Phone(), second.Company(), second.Paragraph()}
+			if strings.Join(a, "|") != strings.Join(b, "|") {
+				t.Errorf("Expecting the same seed to give the same data; Got: %q and %q", a, b)
+			}
+		}
+	}
+}
+
+func TestFakerEmail(t *testing.T) {
+	t.Parallel()
+	reserved := regexp.MustCompile(`@(example\.(com|net|org)|[a-z0-9]+\.test)$`)
+	for _, locale := range []*fake.Locale{fake.EnUS, fake.DeDE} {
+		f := fake.NewRand(randtest.Rand(t), locale)
+		for i := 0; i < 200; i++ {
+			email := f.Email()
+			if _, err := mail.ParseAddress(email); err != nil {
+				t.Errorf("Expecting valid email; Got: %q %v", email, err)
+			}
+			if !reserved.MatchString(email) {
+				t.Errorf("Expecting email at a reserved domain; Got: %q", email)
+			}
+		}
+	}
+}
+
+func TestFakerFormats(t *testing.T) {
+	t.Parallel()
+	f := fake.NewRand(randtest.Rand(t), nil)
+	zip := regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+	address := regexp.MustCompile(`^\d+ \w+ \w+.*, [\w ]+, [A-Z]{2} \d{5}(-\d{4})?$`)
+	phone := regexp.MustCompile(`^(\(([2-9]\d\d)\) |([2-9]\d\d)-|\+1 ([2-9]\d\d) )555[ -]01\d\d$`)
+	for i := 0; i < 200; i++ {
+		if s := f.PostalCode(); !zip.MatchString(s) || s[:5] < "00501" || s[:5] > "99950" {
+			t.Errorf("Expecting zip code; Got: %q", s)
+		}
+		if s := f.Address(); !address.MatchString(s) {
+			t.Errorf("Expecting address; Got: %q", s)
+		}
+		if s := f.Phone(); !phone.MatchString(s) {
+			t.Errorf("Expecting fictional phone number; Got: %q", s)
+		} else if areaCode := strings.Join(phone.FindStringSubmatch(s)[2:], ""); areaCode[1:] == "11" {
+			t.Errorf("Expecting an area code other than N11; Got: %q", s)
+		}
+		if s := f.Sentence(); !strings.HasSuffix(s, ".") || strings.ToUpper(s[:1]) != s[:1] {
+			t.Errorf("Expecting capitalized sentence; Got: %q", s)
+		}
+		if s := f.Words(5); len(strings.Fields(s)) != 5 {
+			t.Errorf("Expecting 5 words; Got: %q", s)
+		}
+		if s := f.Company(); s == "" || strings.ContainsAny(s, "{}") {
+			t.Errorf("Expecting company; Got: %q", s)
+		}
+	}
+}
+
+func TestFakerGermany(t *testing.T) {
+	t.Parallel()
+	f := fake.NewRand(randtest.Rand(t), fake.DeDE)
+	drama := regexp.MustCompile(`^(0|\+49 )(30 23125|40 66969|69 90009|221 4710|89 99998)\d{3}$`)
+	for i := 0; i < 200; i++ {
+		if s := f.Phone(); !drama.MatchString(s) {
+			t.Errorf("Expecting phone number from a range reserved for drama; Got: %q", s)
+		}
+		if s := f.PostalCode(); len(s) != 5 || s < "01001" || s > "99998" {
+			t.Errorf("Expecting postal code between 01001 and 99998; Got: %q", s)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package random
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"math/bits"
+)
+
+// feistelRounds is the number of rounds of the Feistel network, the same as FF1.
+const feistelRounds = 10
+
+// feistel is a keyed pseudo-random permutation of the integers [0, n), built from a balanced
+// Feistel network over the smallest even number of bits that can hold n - 1, with AES as the
+// round function. Values the network maps outside of [0, n) are encrypted again (cycle-walking)
+// until they land inside it, which takes fewer than 4 attempts on average.
+type feistel struct {
+	n        uint64
+	halfBits uint
+	mask     uint64
+	block    cipher.Block
+}
+
+// newFeistel returns a permutation of [0, n) keyed by the 16, 24 or 32 byte AES key.
+// If n is zero, or the key is the wrong length, this will panic.
+func newFeistel(key []byte, n uint64) *feistel {
+	if n == 0 {
+		panic("random: permutation size must be greater than zero")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic("random: " + err.Error())
+	}
+	halfBits := max(1, uint(bits.Len64(n-1)+1)/2)
+	return &feistel{n: n, halfBits: halfBits, mask: 1<<halfBits - 1, block: block}
+}
+
+// round returns the output of the round function for round i, for the half r.
+// The block encrypted is the round number, then n, then r, which is at most 32 bits. Including n
+// means that permutations with the same key but a different size are unrelated to each other.
+func (f *feistel) round(i int, r uint64) uint64 {
+	var b [aes.BlockSize]byte
+	b[0] = byte(i)
+	binary.BigEndian.PutUint64(b[4:], f.n)
+	binary.BigEndian.PutUint32(b[12:], uint32(r))
+	f.block.Encrypt(b[:], b[:])
+	return binary.BigEndian.Uint64(b[:]) & f.mask
+}
+
+// encrypt maps x, which must be in [0, n), to its position in the permutation.
+func (f *feistel) encrypt(x uint64) uint64 {
+	for {
+		l, r := x>>f.halfBits, x&f.mask
+		for i := 0; i < feistelRounds; i++ {
+			l, r = r, l^f.round(i, r)
+		}
+		x = l<<f.halfBits | r
+		if x < f.n {
+			return x
+		}
+	}
+}
+
+// decrypt is the inverse of encrypt.
+func (f *feistel) decrypt(x uint64) uint64 {
+	for {
+		l, r := x>>f.halfBits, x&f.mask
+		for i := feistelRounds - 1; i >= 0; i-- {
+			l, r = r^f.round(i, l), l
+		}
+		x = l<<f.halfBits | r
+		if x < f.n {
+			return x
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Randomizer can be implemented by types that want to control how Fill gives them random values.
+// Fill calls Randomize on a pointer to the value.
+type Randomizer interface {
+	Randomize(rand *rand.Rand)
+}
+
+// FillOptions controls how Fill populates values.
+type FillOptions struct {
+	// Rand is the source of randomness. Use a *rand.Rand with a fixed seed for reproducible fixtures.
+	// If nil, this will panic.
+	Rand *rand.Rand
+
+	// MinLen and MaxLen are the default inclusive range of lengths for strings, slices and maps
+	// without a len tag. If both are zero, the range is 0 to 8.
+	MinLen int
+	MaxLen int
+
+	// MaxDepth is how many pointers, slices and maps deep to fill, to stop recursive types
+	// from growing forever. Values nested deeper are left as their zero value.
+	// If zero, the default is 5.
+	MaxDepth int
+}
+
+// fillTag is a parsed `random:"..."` struct tag.
+type fillTag struct {
+	skip bool
+
+	// field is the name of the struct field, for panic messages
+	field string
+
+	// hasLen is true if minLen and maxLen were given, as len=5 or len=5..10 (inclusive)
+	hasLen         bool
+	minLen, maxLen int
+
+	// alphabet is the characters strings are made from
+	alphabet []byte
+
+	// hasMin and hasMax are true if min and max were given, as [min, max),
+	// and minOption and maxOption are the options they were given by
+	hasMin, hasMax       bool
+	min, max             float64
+	minOption, maxOption string
+}
+
+// fillAlphabets are the alphabet names that can be used in struct tags.
+var fillAlphabets = map[string][]byte{
+	"hex":                   HexBytes,
+	"alphabet":              AlphabetBytes,
+	"alphabetupperandlower": AlphabetUpperAndLowerBytes,
+	"alphanumeric":          AlphaNumericBytes,
+	"base64url":             Base64URLBytes,
+	"base64std":             Base64StdBytes,
+	"crockford32":           Crockford32Bytes,
+	"alphabetnovowels":      AlphabetNoVowelsBytes,
+	"alphanumericnovowels":  AlphaNumericNoVowelsBytes,
+	"unambiguous":           UnambiguousBytes,
+	"unambiguousnovowels":   UnambiguousNoVowelsBytes,
+}
+
+// Fill populates the value that v points to with random data from opts.Rand, for
+// reproducible test fixtures and property tests.
+// Structs, arrays, slices, maps, pointers, strings, bools, all integer, float and complex kinds,
+// and time.Time are supported. Unexported fields, interfaces, channels and functions are left alone.
+// Types implementing Randomizer are filled by calling their Randomize method.
+//
+// Struct fields can be controlled with a `random:"..."` tag of comma separated options:
+//
+//	len=5..10      the inclusive range of lengths for strings, slices and maps (or len=5 for exactly 5)
+//	alphabet=hex   the characters for strings, by lower case name of an alphabet constant in this package
+//	min=0,max=100  the range, [min, max), for numbers, or for the elements of slices, arrays and maps
+//	-              leave the field alone (on its own, as random:"-")
+//
+// Strings default to AlphaNumeric. Integers default to the full range of their type,
+// and a min or max outside of that range is invalid. Floats default to [-1e6, 1e6), and times to between 1970 and 2100 in UTC.
+// A float with only a min or max that is outside the default range gets a range of the same
+// width that starts at the min or ends at the max.
+// If v is not a non-nil pointer, opts.Rand is nil, or a tag is invalid, this will panic.
+func Fill(v any, opts FillOptions) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		panic("random: Fill requires a non-nil pointer")
+	}
+	if opts.Rand == nil {
+		panic("random: FillOptions.Rand must not be nil")
+	}
+	if opts.MinLen == 0 && opts.MaxLen == 0 {
+		opts.MaxLen = 8
+	}
+	if opts.MinLen < 0 || opts.MaxLen < opts.MinLen {
+		panic("random: FillOptions MinLen must not be negative or greater than MaxLen")
+	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = 5
+	}
+	f := filler{opts: opts}
+	f.fill(rv.Elem(), fillTag{}, 0)
+}
+
+// filler holds the options while filling a value.
+type filler struct {
+	opts FillOptions
+}
+
+var (
+	randomizerType = reflect.TypeFor[Randomizer]()
+	timeType       = reflect.TypeFor[time.Time]()
+	fillTimeStart  = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	fillTimeEnd    = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// fill sets v to a random value, following the tag.
+func (f *filler) fill(v reflect.Value, tag fillTag, depth int) {
+	if tag.skip || !v.CanSet() {
+		return
+	}
+	r := f.opts.Rand
+
+	if v.CanAddr() && v.Addr().Type().Implements(randomizerType) {
+		v.Addr().Interface().(Randomizer).Randomize(r)
+		return
+	}
+	if v.Type() == timeType {
+		v.Set(reflect.ValueOf(PseudoRandomTimeRand(r, fillTimeStart, fillTimeEnd).UTC()))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// The full range of the type, narrowed by the tag
+		bitSize := v.Type().Bits()
+		limit := math.Ldexp(1, bitSize-1)
+		tag.checkBounds(-limit, limit)
+		lo, hi := int64(-1)<<(bitSize-1), int64(uint64(1)<<(bitSize-1)-1)
+		if tag.hasMin {
+			lo = int64(math.Ceil(tag.min))
+		}
+		if tag.hasMax && tag.max < limit {
+			hi = int64(math.Ceil(tag.max)) - 1
+		}
+		if hi < lo {
+			panic("random: tag min and max leave no possible integers")
+		}
+		v.SetInt(lo + int64(f.uint64n(uint64(hi)-uint64(lo)+1)))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		// The full range of the type, narrowed by the tag
+		bitSize := v.Type().Bits()
+		limit := math.Ldexp(1, bitSize)
+		tag.checkBounds(0, limit)
+		lo, hi := uint64(0), uint64(math.MaxUint64)>>(64-bitSize)
+		if tag.hasMin {
+			lo = uint64(math.Ceil(tag.min))
+		}
+		if tag.hasMax && tag.max < limit {
+			hi = uint64(math.Ceil(tag.max)) - 1
+		}
+		if hi < lo {
+			panic("random: tag min and max leave no possible integers")
+		}
+		v.SetUint(lo + f.uint64n(hi-lo+1))
+
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(f.float(tag))
+
+	case reflect.Complex64, reflect.Complex128:
+		v.SetComplex(complex(f.float(tag), f.float(tag)))
+
+	case reflect.String:
+		alphabet := tag.alphabet
+		if alphabet == nil {
+			alphabet = AlphaNumericBytes
+		}
+		v.SetString(PseudoRandomStringBytesRand(r, f.length(tag), alphabet))
+
+	case reflect.Pointer:
+		if depth >= f.opts.MaxDepth {
+			return
+		}
+		ptr := reflect.New(v.Type().Elem())
+		f.fill(ptr.Elem(), tag, depth+1)
+		v.Set(ptr)
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			f.fill(v.Index(i), tag, depth)
+		}
+
+	case reflect.Slice:
+		if depth >= f.opts.MaxDepth {
+			return
+		}
+		length := f.length(tag)
+		slice := reflect.MakeSlice(v.Type(), length, length)
+		elemTag := tag.elementTag()
+		for i := 0; i < length; i++ {
+			f.fill(slice.Index(i), elemTag, depth+1)
+		}
+		v.Set(slice)
+
+	case reflect.Map:
+		if depth >= f.opts.MaxDepth {
+			return
+		}
+		length := f.length(tag)
+		m := reflect.MakeMapWithSize(v.Type(), length)
+		elemTag := tag.elementTag()
+		for i := 0; i < length; i++ {
+			key := reflect.New(v.Type().Key()).Elem()
+			f.fill(key, elemTag, depth+1)
+			value := reflect.New(v.Type().Elem()).Elem()
+			f.fill(value, elemTag, depth+1)
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f.fill(v.Field(i), parseFillTag(t.Field(i)), depth)
+		}
+	}
+}
+
+// uint64n returns a random number between [0, n), where an n of zero means the full range of uint64.
+func (f *filler) uint64n(n uint64) uint64 {
+	if n == 0 {
+		return f.opts.Rand.Uint64()
+	}
+	return newRandomIndexer(f.opts.Rand.Uint64, n).next()
+}
+
+// float returns a random float following the tag.
+func (f *filler) float(tag fillTag) float64 {
+	lo, hi := -1e6, 1e6
+	if tag.hasMin {
+		lo = tag.min
+		if !tag.hasMax && lo >= hi {
+			hi = lo + 2e6
+		}
+	}
+	if tag.hasMax {
+		hi = tag.max
+		if !tag.hasMin && hi <= lo {
+			lo = hi - 2e6
+		}
+	}
+	return lo + f.opts.Rand.Float64()*(hi-lo)
+}
+
+// length returns a random length following the tag, or the options if the tag does not have a len.
+func (f *filler) length(tag fillTag) int {
+	lo, hi := f.opts.MinLen, f.opts.MaxLen
+	if tag.hasLen {
+		lo, hi = tag.minLen, tag.maxLen
+	}
+	return int(PseudoRandomInt63Rand(f.opts.Rand, int64(lo), int64(hi)+1))
+}
+
+// checkBounds panics if the min or max of the tag is outside [lo, hi), the range of an integer type.
+// As max is exclusive, it may be hi, but not lo.
+func (tag fillTag) checkBounds(lo, hi float64) {
+	if tag.hasMin && !(tag.min >= lo && tag.min < hi) {
+		tag.invalid(tag.minOption)
+	}
+	if tag.hasMax && !(tag.max > lo && tag.max <= hi) {
+		tag.invalid(tag.maxOption)
+	}
+}
+
+// invalid panics for an option of the tag.
+func (tag fillTag) invalid(option string) {
+	panic("random: invalid tag option " + strconv.Quote(option) + " on field " + tag.field)
+}
+
+// elementTag returns the tag to use for the elements of a slice or map.
+// The len applies to the slice or map itself, while everything else applies to the elements.
+func (tag fillTag) elementTag() fillTag {
+	tag.hasLen = false
+	return tag
+}
+
+// parseFillTag parses the `random:"..."` tag of a struct field.
+// If the tag is invalid, this will panic.
+func parseFillTag(field reflect.StructField) fillTag {
+	tag := fillTag{field: field.Name}
+	value, ok := field.Tag.Lookup("random")
+	if !ok || value == "" {
+		return tag
+	}
+	if value == "-" {
+		tag.skip = true
+		return tag
+	}
+
+	for _, option := range strings.Split(value, ",") {
+		key, val, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "len":
+			lo, hi, isRange := strings.Cut(val, "..")
+			if !isRange {
+				hi = lo
+			}
+			var err1, err2 error
+			tag.minLen, err1 = strconv.Atoi(lo)
+			tag.maxLen, err2 = strconv.Atoi(hi)
+			if err1 != nil || err2 != nil || tag.minLen < 0 || tag.maxLen < tag.minLen {
+				tag.invalid(option)
+			}
+			tag.hasLen = true
+
+		case "alphabet":
+			if tag.alphabet = fillAlphabets[strings.ToLower(val)]; tag.alphabet == nil {
+				tag.invalid(option)
+			}
+
+		case "min", "max":
+			n, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				tag.invalid(option)
+			}
+			if key == "min" {
+				tag.min, tag.hasMin, tag.minOption = n, true, option
+			} else {
+				tag.max, tag.hasMax, tag.maxOption = n, true, option
+			}
+
+		default:
+			tag.invalid(option)
+		}
+	}
+	if tag.hasMin && tag.hasMax && tag.max <= tag.min {
+		panic("random: tag max must be greater than min on field " + field.Name)
+	}
+	return tag
+}
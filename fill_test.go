@@ -0,0 +1,166 @@
+package random_test
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+type fillNested struct {
+	Values []int16 `random:"len=3,min=-5,max=5"`
+	Lookup map[string]uint8
+	Next   *fillNested
+}
+
+type fillCustom struct {
+	value string
+}
+
+func (c *fillCustom) Randomize(rand *rand.Rand) {
+	c.value = "custom" + random.PseudoRandomHexRand(rand, 4)
+}
+
+type fillFixture struct {
+	ID       string  `random:"len=5..10,alphabet=hex"`
+	Code     string  `random:"len=6,alphabet=unambiguous"`
+	Age      int     `random:"min=0,max=100"`
+	Small    uint8   `random:"min=10,max=12"`
+	Score    float64 `random:"min=0.5,max=1"`
+	Skipped  string  `random:"-"`
+	Big      int64
+	Enabled  bool
+	Created  time.Time
+	Tags     []string `random:"len=2..4,alphabet=alphabet"`
+	Grid     [3]uint32
+	Nested   fillNested
+	Pointer  *fillNested
+	Custom   fillCustom
+	CustomP  *fillCustom
+	Any      any
+	internal int
+}
+
+func TestFill(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	for i := 0; i < 200; i++ {
+		var v fillFixture
+		random.Fill(&v, random.FillOptions{Rand: source, MaxDepth: 3})
+		if len(v.ID) < 5 || len(v.ID) > 10 || strings.Trim(v.ID, random.Hex) != "" {
+			t.Errorf("Expecting 5 to 10 hex characters; Got: %q", v.ID)
+		}
+		if len(v.Code) != 6 || strings.Trim(v.Code, random.Unambiguous) != "" {
+			t.Errorf("Expecting 6 unambiguous characters; Got: %q", v.Code)
+		}
+		if v.Age < 0 || v.Age >= 100 || v.Small < 10 || v.Small >= 12 || v.Score < 0.5 || v.Score >= 1 {
+			t.Errorf("Expecting numbers in range; Got: %d %d %f", v.Age, v.Small, v.Score)
+		}
+		if v.Skipped != "" || v.Any != nil || v.internal != 0 {
+			t.Errorf("Expecting skipped fields to be left alone; Got: %+v", v)
+		}
+		if v.Created.Year() < 1970 || v.Created.Year() >= 2100 {
+			t.Errorf("Expecting time between 1970 and 2100; Got: %s", v.Created)
+		}
+		if len(v.Tags) < 2 || len(v.Tags) > 4 {
+			t.Errorf("Expecting 2 to 4 tags; Got: %q", v.Tags)
+		}
+		if len(v.Nested.Values) != 3 {
+			t.Errorf("Expecting 3 nested values; Got: %v", v.Nested.Values)
+		}
+		for _, n := range v.Nested.Values {
+			if n < -5 || n >= 5 {
+				t.Errorf("Expecting nested values in [-5, 5); Got: %d", n)
+			}
+		}
+		if !strings.HasPrefix(v.Custom.value, "custom") || v.CustomP == nil || !strings.HasPrefix(v.CustomP.value, "custom") {
+			t.Errorf("Expecting Randomizer to be used; Got: %+v %+v", v.Custom, v.CustomP)
+		}
+		// MaxDepth of 3 allows Pointer, Pointer.Next, and Pointer.Next.Next, but no further
+		if v.Pointer == nil || v.Pointer.Next == nil || v.Pointer.Next.Next == nil || v.Pointer.Next.Next.Next != nil {
+			t.Errorf("Expecting pointers to be filled to depth 3")
+		}
+	}
+}
+
+func TestFillOneSidedFloatRange(t *testing.T) {
+	t.Parallel()
+	var v struct {
+		AboveDefault float64 `random:"min=5000000"`
+		BelowDefault float32 `random:"max=-5000000"`
+		WithinMin    float64 `random:"min=5"`
+		WithinMax    float64 `random:"max=5"`
+	}
+	source := randtest.Rand(t)
+	for i := 0; i < 200; i++ {
+		random.Fill(&v, random.FillOptions{Rand: source})
+		if v.AboveDefault < 5e6 || v.AboveDefault >= 7e6 || v.BelowDefault >= -5e6 || v.BelowDefault < -7e6 {
+			t.Errorf("Expecting floats beyond the default range; Got: %v %v", v.AboveDefault, v.BelowDefault)
+		}
+		if v.WithinMin < 5 || v.WithinMin >= 1e6 || v.WithinMax < -1e6 || v.WithinMax >= 5 {
+			t.Errorf("Expecting floats within the default range; Got: %v %v", v.WithinMin, v.WithinMax)
+		}
+	}
+}
+
+func TestFillDeterministic(t *testing.T) {
+	t.Parallel()
+	seed := randtest.Seed(t)
+	var a, b fillFixture
+	random.Fill(&a, random.FillOptions{Rand: rand.New(rand.NewSource(seed))})
+	random.Fill(&b, random.FillOptions{Rand: rand.New(rand.NewSource(seed))})
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("Expecting the same seed to give the same value; Got: %+v and %+v", a, b)
+	}
+}
+
+func TestFillInvalidTag(t *testing.T) {
+	t.Parallel()
+	for _, v := range []any{
+		&struct {
+			Name string `random:"alphabet=klingon"`
+		}{},
+		// Bounds outside the range of the integer type
+		&struct {
+			Big int64 `random:"min=1e19"`
+		}{},
+		&struct {
+			Huge int64 `random:"max=1e300"`
+		}{},
+		&struct {
+			Small int8 `random:"min=-129"`
+		}{},
+		&struct {
+			Values []uint8 `random:"len=3,max=257"`
+		}{},
+		&struct {
+			Negative *uint `random:"min=-1"`
+		}{},
+		&struct {
+			Zero uint32 `random:"max=0"`
+		}{},
+		&struct {
+			NaN int `random:"min=NaN"`
+		}{},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expecting panic for invalid tag on %T", v)
+				}
+			}()
+			random.Fill(v, random.FillOptions{Rand: rand.New(rand.NewSource(1))})
+		}()
+	}
+
+	// The full range of the type is valid, with max exclusive
+	var v struct {
+		Full  int8   `random:"min=-128,max=128"`
+		UFull uint64 `random:"min=0,max=18446744073709551616"`
+	}
+	random.Fill(&v, random.FillOptions{Rand: rand.New(rand.NewSource(1))})
+}
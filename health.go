@@ -0,0 +1,168 @@
+package random
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// Errors returned by HealthCheckedReader when its source fails a continuous health test.
+var (
+	// ErrRepetitionCountTest means the source repeated the same byte too many times in a row,
+	// which suggests it is stuck.
+	ErrRepetitionCountTest = errors.New("random: entropy source failed the repetition count test")
+
+	// ErrAdaptiveProportionTest means one byte value was too common in a window of the output,
+	// which suggests the source is biased.
+	ErrAdaptiveProportionTest = errors.New("random: entropy source failed the adaptive proportion test")
+)
+
+// HealthConfig controls the continuous health tests of a HealthCheckedReader.
+type HealthConfig struct {
+	// MinEntropy is the min-entropy per byte, in bits, that the source is claimed to have.
+	// If zero, the default is 8, meaning full entropy, as for crypto/rand or a conditioned hardware RNG.
+	// Raw noise sources should claim less, to avoid false alarms.
+	MinEntropy float64
+
+	// FalsePositiveRate is the chance that a healthy source fails a test at any given byte.
+	// If zero, the default is 2^-40. SP 800-90B recommends between 2^-20 and 2^-40.
+	FalsePositiveRate float64
+
+	// OnFailure, if not nil, is called once with the error when the source first fails a test.
+	OnFailure func(error)
+}
+
+// aptWindowSize is the adaptive proportion test window for non-binary samples, from SP 800-90B 4.4.2.
+const aptWindowSize = 512
+
+// HealthCheckedReader wraps an entropy source, such as a hardware RNG device file, running the
+// NIST SP 800-90B continuous health tests on every byte read through it: the repetition count test,
+// which detects a source that is stuck, and the adaptive proportion test, which detects one that
+// has become biased. Once a test fails, the bytes from that read are discarded, and this and every
+// later Read return the error, as the source can no longer be trusted.
+// It can be used anywhere an io.Reader of randomness is accepted, such as crypto/rand.Int.
+// It is safe for concurrent use.
+type HealthCheckedReader struct {
+	source    io.Reader
+	onFailure func(error)
+	rctCutoff int
+	aptCutoff int
+
+	mu  sync.Mutex
+	err error
+
+	// Repetition count test state
+	rctValue byte
+	rctCount int
+
+	// Adaptive proportion test state
+	aptValue    byte
+	aptCount    int
+	aptPosition int
+}
+
+// NewHealthCheckedReader returns a HealthCheckedReader for the source.
+// If the config's MinEntropy is not in (0, 8], or FalsePositiveRate is not in (0, 1), this will panic.
+func NewHealthCheckedReader(source io.Reader, config HealthConfig) *HealthCheckedReader {
+	if config.MinEntropy == 0 {
+		config.MinEntropy = 8
+	}
+	if config.FalsePositiveRate == 0 {
+		config.FalsePositiveRate = math.Ldexp(1, -40)
+	}
+	if !(config.MinEntropy > 0 && config.MinEntropy <= 8) {
+		panic("random: MinEntropy must be greater than 0 and at most 8")
+	}
+	if !(config.FalsePositiveRate > 0 && config.FalsePositiveRate < 1) {
+		panic("random: FalsePositiveRate must be between 0 and 1")
+	}
+	return &HealthCheckedReader{
+		source:    source,
+		onFailure: config.OnFailure,
+		rctCutoff: 1 + int(math.Ceil(-math.Log2(config.FalsePositiveRate)/config.MinEntropy)),
+		aptCutoff: 1 + criticalBinomial(aptWindowSize, math.Exp2(-config.MinEntropy), config.FalsePositiveRate),
+	}
+}
+
+// criticalBinomial returns the smallest k where the chance of more than k successes out of n trials,
+// each with probability p, is at most alpha (the CRITBINOM(n, p, 1-alpha) used by SP 800-90B).
+func criticalBinomial(n int, p, alpha float64) int {
+	cumulative := 0.0
+	for k := 0; k < n; k++ {
+		lgN, _ := math.Lgamma(float64(n + 1))
+		lgK, _ := math.Lgamma(float64(k + 1))
+		lgNK, _ := math.Lgamma(float64(n - k + 1))
+		cumulative += math.Exp(lgN - lgK - lgNK + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+		if 1-cumulative <= alpha {
+			return k
+		}
+	}
+	return n
+}
+
+// Read implements io.Reader, reading from the source and testing every byte.
+// If the source fails a test, it returns zero bytes and ErrRepetitionCountTest or
+// ErrAdaptiveProportionTest, as will every later call.
+// Errors from the source itself are returned as they are.
+// OnFailure is called after the lock is released, so it may call Err or Read.
+func (r *HealthCheckedReader) Read(b []byte) (int, error) {
+	n, failed, err := r.read(b)
+	if failed && r.onFailure != nil {
+		r.onFailure(err)
+	}
+	return n, err
+}
+
+// read reads from the source and tests every byte, while holding the lock.
+// It returns true if this read is the one that failed a test.
+func (r *HealthCheckedReader) read(b []byte) (int, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return 0, false, r.err
+	}
+
+	n, err := r.source.Read(b)
+	for i := 0; i < n; i++ {
+		if testErr := r.test(b[i]); testErr != nil {
+			clear(b[:n])
+			r.err = testErr
+			return 0, true, testErr
+		}
+	}
+	return n, false, err
+}
+
+// test runs both health tests on the next byte from the source.
+func (r *HealthCheckedReader) test(sample byte) error {
+	// Repetition count test, SP 800-90B 4.4.1
+	if r.rctCount > 0 && sample == r.rctValue {
+		r.rctCount++
+		if r.rctCount >= r.rctCutoff {
+			return fmt.Errorf("%w: byte 0x%02x repeated %d times in a row", ErrRepetitionCountTest, sample, r.rctCount)
+		}
+	} else {
+		r.rctValue, r.rctCount = sample, 1
+	}
+
+	// Adaptive proportion test, SP 800-90B 4.4.2
+	if r.aptPosition == 0 {
+		r.aptValue, r.aptCount = sample, 1
+	} else if sample == r.aptValue {
+		r.aptCount++
+		if r.aptCount >= r.aptCutoff {
+			return fmt.Errorf("%w: byte 0x%02x seen %d times in a window of %d", ErrAdaptiveProportionTest, sample, r.aptCount, aptWindowSize)
+		}
+	}
+	r.aptPosition = (r.aptPosition + 1) % aptWindowSize
+	return nil
+}
+
+// Err returns the error the source failed a health test with, or nil if it is healthy.
+func (r *HealthCheckedReader) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
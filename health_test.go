@@ -0,0 +1,79 @@
+package random_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestHealthCheckedReaderHealthy(t *testing.T) {
+	t.Parallel()
+	reader := random.NewHealthCheckedReader(randtest.Rand(t), random.HealthConfig{})
+	b := make([]byte, 1<<20)
+	if _, err := io.ReadFull(reader, b); err != nil {
+		t.Fatalf("Expecting a healthy source to pass; Got: %v", err)
+	}
+	if reader.Err() != nil {
+		t.Errorf("Expecting no error; Got: %v", reader.Err())
+	}
+}
+
+func TestHealthCheckedReaderRepetitionCount(t *testing.T) {
+	t.Parallel()
+	// With the defaults of full entropy and a false positive rate of 2^-40, the cutoff is 1 + 40/8 = 6
+	reader := random.NewHealthCheckedReader(bytes.NewReader([]byte{1, 7, 7, 7, 7, 7, 2}), random.HealthConfig{})
+	if _, err := reader.Read(make([]byte, 7)); err != nil {
+		t.Fatalf("Expecting 5 repeats to pass; Got: %v", err)
+	}
+
+	var failures []error
+	reader = random.NewHealthCheckedReader(bytes.NewReader(bytes.Repeat([]byte{7}, 100)), random.HealthConfig{
+		OnFailure: func(err error) {
+			// The callback can use the reader without deadlocking
+			if reader.Err() != err {
+				t.Errorf("Expecting Err to return the failure; Got: %v", reader.Err())
+			}
+			failures = append(failures, err)
+		},
+	})
+	b := make([]byte, 10)
+	n, err := reader.Read(b)
+	if !errors.Is(err, random.ErrRepetitionCountTest) || n != 0 {
+		t.Fatalf("Expecting ErrRepetitionCountTest and no bytes; Got: %d, %v", n, err)
+	}
+	if !bytes.Equal(b, make([]byte, 10)) {
+		t.Errorf("Expecting the failed bytes to be cleared; Got: %v", b)
+	}
+	if _, err = reader.Read(b); !errors.Is(err, random.ErrRepetitionCountTest) {
+		t.Errorf("Expecting the failure to persist; Got: %v", err)
+	}
+	if len(failures) != 1 || !errors.Is(failures[0], random.ErrRepetitionCountTest) {
+		t.Errorf("Expecting the callback to be called once; Got: %v", failures)
+	}
+}
+
+func TestHealthCheckedReaderAdaptiveProportion(t *testing.T) {
+	t.Parallel()
+	// Every other byte is zero, so the repetition count test passes, but zero is half of each window
+	r := randtest.Rand(t)
+	biased := make([]byte, 4096)
+	for i := 1; i < len(biased); i += 2 {
+		biased[i-1] = 0
+		biased[i] = byte(1 + r.Intn(255))
+	}
+	reader := random.NewHealthCheckedReader(bytes.NewReader(biased), random.HealthConfig{})
+	_, err := io.ReadAll(reader)
+	if !errors.Is(err, random.ErrAdaptiveProportionTest) {
+		t.Errorf("Expecting ErrAdaptiveProportionTest; Got: %v", err)
+	}
+
+	// A source claiming only 1 bit of entropy per byte tolerates the same bias
+	reader = random.NewHealthCheckedReader(bytes.NewReader(biased), random.HealthConfig{MinEntropy: 1})
+	if _, err = io.ReadAll(reader); err != nil {
+		t.Errorf("Expecting a low entropy claim to pass; Got: %v", err)
+	}
+}
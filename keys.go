@@ -0,0 +1,113 @@
+package random
+
+import (
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+// Sizes in bytes of keys, nonces and salts for common primitives.
+const (
+	AES128KeySize             = 16
+	AES256KeySize             = 32
+	GCMNonceSize              = 12 // AES-GCM standard nonce
+	ChaCha20Poly1305NonceSize = 12
+	XChaCha20NonceSize        = 24 // XChaCha20-Poly1305 extended nonce
+	SaltSize                  = 16 // Recommended for Argon2, scrypt and PBKDF2, and the size bcrypt uses
+)
+
+// GCMRandomNonceLimit is the most messages NIST SP 800-38D allows to be encrypted with a single
+// AES-GCM key when nonces are random, to keep the chance of a repeat below 2^-32.
+const GCMRandomNonceLimit = 1 << 32
+
+// SecureRandomAES128Key uses crypto/rand to return a random AES-128 key.
+// Use SecureRandomSecret(AES128KeySize) for a key that can be wiped.
+func SecureRandomAES128Key() []byte {
+	return SecureRandomBytes(AES128KeySize)
+}
+
+// SecureRandomAES256Key uses crypto/rand to return a random AES-256 key,
+// which is also the key size for ChaCha20-Poly1305 and XChaCha20-Poly1305.
+// Use SecureRandomSecret(AES256KeySize) for a key that can be wiped.
+func SecureRandomAES256Key() []byte {
+	return SecureRandomBytes(AES256KeySize)
+}
+
+// SecureRandomGCMNonce uses crypto/rand to return a random AES-GCM nonce.
+// Random nonces must not be used for more than GCMRandomNonceLimit messages with the same key;
+// use a NonceGenerator to safely encrypt more.
+func SecureRandomGCMNonce() []byte {
+	return SecureRandomBytes(GCMNonceSize)
+}
+
+// SecureRandomChaCha20Poly1305Nonce uses crypto/rand to return a random ChaCha20-Poly1305 nonce.
+// At 96 bits, random nonces have the same limits as AES-GCM, see SecureRandomGCMNonce.
+func SecureRandomChaCha20Poly1305Nonce() []byte {
+	return SecureRandomBytes(ChaCha20Poly1305NonceSize)
+}
+
+// SecureRandomXChaCha20Nonce uses crypto/rand to return a random XChaCha20-Poly1305 extended nonce,
+// which is long enough that random nonces can be used for practically any number of messages.
+func SecureRandomXChaCha20Nonce() []byte {
+	return SecureRandomBytes(XChaCha20NonceSize)
+}
+
+// SecureRandomHMACKey uses crypto/rand to return a random HMAC key for the hash function,
+// as long as the hash's output, which RFC 2104 recommends as the minimum.
+// If the hash function is unknown, this will panic.
+func SecureRandomHMACKey(hash crypto.Hash) []byte {
+	return SecureRandomBytes(hash.Size())
+}
+
+// SecureRandomSalt uses crypto/rand to return a random salt for password hashing, such as Argon2 or scrypt.
+func SecureRandomSalt() []byte {
+	return SecureRandomBytes(SaltSize)
+}
+
+// ErrNonceLimitExceeded is returned by NonceGenerator when its message limit has been reached.
+var ErrNonceLimitExceeded = errors.New("random: nonce message limit exceeded")
+
+// NonceGenerator creates nonces that are guaranteed to be unique for a key, by combining a random
+// prefix with a counter, as in the deterministic construction of NIST SP 800-38D.
+// The last 8 bytes of each nonce are the counter, and the bytes before it are chosen randomly
+// once per generator, so that separate generators for the same key are unlikely to collide.
+// Use a single generator per key for guaranteed uniqueness. It is thread-safe.
+type NonceGenerator struct {
+	mu      sync.Mutex
+	prefix  []byte
+	counter uint64
+	limit   uint64
+}
+
+// NewNonceGenerator returns a NonceGenerator for nonces of size bytes, such as GCMNonceSize,
+// which returns ErrNonceLimitExceeded once limit nonces have been created, at which point the key must be rotated.
+// If size is less than 8, or limit is zero, this will panic.
+func NewNonceGenerator(size int, limit uint64) *NonceGenerator {
+	if size < 8 {
+		panic("random: nonce size must be at least 8 bytes")
+	}
+	if limit == 0 {
+		panic("random: nonce limit must be greater than zero")
+	}
+	return &NonceGenerator{prefix: SecureRandomBytes(size - 8), limit: limit}
+}
+
+// Next returns a new nonce, or ErrNonceLimitExceeded if the limit has been reached.
+func (g *NonceGenerator) Next() ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.counter >= g.limit {
+		return nil, ErrNonceLimitExceeded
+	}
+	nonce := binary.BigEndian.AppendUint64(append(make([]byte, 0, len(g.prefix)+8), g.prefix...), g.counter)
+	g.counter++
+	return nonce, nil
+}
+
+// Remaining returns how many more nonces can be created before the limit is reached.
+func (g *NonceGenerator) Remaining() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.limit - g.counter
+}
@@ -0,0 +1,84 @@
+package random_test
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	_ "crypto/sha256"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/veqryn/go-random"
+)
+
+func TestSecureRandomKeys(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		result   []byte
+		expected int
+	}{
+		{"AES-128 key", random.SecureRandomAES128Key(), 16},
+		{"AES-256 key", random.SecureRandomAES256Key(), 32},
+		{"GCM nonce", random.SecureRandomGCMNonce(), 12},
+		{"ChaCha20-Poly1305 nonce", random.SecureRandomChaCha20Poly1305Nonce(), 12},
+		{"XChaCha20 nonce", random.SecureRandomXChaCha20Nonce(), 24},
+		{"HMAC-SHA256 key", random.SecureRandomHMACKey(crypto.SHA256), 32},
+		{"salt", random.SecureRandomSalt(), 16},
+	}
+	for _, tc := range tests {
+		if len(tc.result) != tc.expected {
+			t.Errorf("Expecting %s length %d; Got: %d", tc.name, tc.expected, len(tc.result))
+		}
+	}
+
+	// The key and nonce work with the standard library
+	block, err := aes.NewCipher(random.SecureRandomAES256Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm.Seal(nil, random.SecureRandomGCMNonce(), []byte("hello"), nil)
+}
+
+func TestNonceGenerator(t *testing.T) {
+	t.Parallel()
+	g := random.NewNonceGenerator(random.GCMNonceSize, 1000)
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				nonce, err := g.Next()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if len(nonce) != random.GCMNonceSize {
+					t.Errorf("Expecting nonce length %d; Got: %d", random.GCMNonceSize, len(nonce))
+				}
+				mu.Lock()
+				if seen[string(nonce)] {
+					t.Errorf("Expecting unique nonces; Got repeat: %x", nonce)
+				}
+				seen[string(nonce)] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if g.Remaining() != 0 {
+		t.Errorf("Expecting no nonces remaining; Got: %d", g.Remaining())
+	}
+	if _, err := g.Next(); !errors.Is(err, random.ErrNonceLimitExceeded) {
+		t.Errorf("Expecting ErrNonceLimitExceeded; Got: %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package random
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// KSUID is a K-Sortable Unique IDentifier: a 32 bit timestamp in seconds since the KSUID epoch,
+// followed by a 128 bit random payload from crypto/rand. Its string form is 27 base62 characters,
+// and sorts in the same order as the timestamps, to the second.
+// It is compatible with github.com/segmentio/ksuid.
+type KSUID [20]byte
+
+// ksuidEpoch is the start of KSUID timestamps, 2014-05-13 16:53:20 UTC, which extends their range to 2150.
+const ksuidEpoch = 1400000000
+
+// ksuidStringLength is the length of the base62 string form.
+const ksuidStringLength = 27
+
+// ksuidBase62 is the base62 alphabet used by KSUID, which sorts in ASCII order.
+const ksuidBase62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrInvalidKSUID is returned when parsing or scanning a value that is not a KSUID.
+var ErrInvalidKSUID = errors.New("random: invalid KSUID")
+
+// NewKSUID returns a new KSUID for the current time.
+func NewKSUID() KSUID {
+	return NewKSUIDWithTime(time.Now())
+}
+
+// NewKSUIDWithTime returns a new KSUID for the given time, which is truncated to the second.
+// If the time is outside of the range KSUIDs can represent (2014 to 2150), this will panic.
+func NewKSUIDWithTime(t time.Time) KSUID {
+	seconds := t.Unix() - ksuidEpoch
+	if seconds < 0 || seconds > 1<<32-1 {
+		panic("random: time is outside of the range of KSUID")
+	}
+	var k KSUID
+	binary.BigEndian.PutUint32(k[:4], uint32(seconds))
+	secureFill(k[4:])
+	return k
+}
+
+// ParseKSUID parses the 27 character base62 string form of a KSUID.
+func ParseKSUID(s string) (KSUID, error) {
+	var k KSUID
+	if len(s) != ksuidStringLength {
+		return k, ErrInvalidKSUID
+	}
+	// The 160 bit number, as 5 big-endian uint32 words
+	var words [5]uint32
+	for i := 0; i < len(s); i++ {
+		digit := bytes.IndexByte([]byte(ksuidBase62), s[i])
+		if digit < 0 {
+			return k, ErrInvalidKSUID
+		}
+		carry := uint64(digit)
+		for j := len(words) - 1; j >= 0; j-- {
+			v := uint64(words[j])*62 + carry
+			words[j], carry = uint32(v), v>>32
+		}
+		if carry != 0 {
+			return k, ErrInvalidKSUID
+		}
+	}
+	for i, w := range words {
+		binary.BigEndian.PutUint32(k[4*i:], w)
+	}
+	return k, nil
+}
+
+// String returns the 27 character base62 form of the KSUID.
+func (k KSUID) String() string {
+	var words [5]uint32
+	for i := range words {
+		words[i] = binary.BigEndian.Uint32(k[4*i:])
+	}
+	result := bytes.Repeat([]byte{'0'}, ksuidStringLength)
+	for i := len(result) - 1; words != [5]uint32{}; i-- {
+		// Divide the 160 bit number by 62, keeping the remainder as the next digit
+		var remainder uint64
+		for j := range words {
+			v := remainder<<32 | uint64(words[j])
+			words[j], remainder = uint32(v/62), v%62
+		}
+		result[i] = ksuidBase62[remainder]
+	}
+	return string(result)
+}
+
+// Timestamp returns the raw timestamp, in seconds since the KSUID epoch.
+func (k KSUID) Timestamp() uint32 {
+	return binary.BigEndian.Uint32(k[:4])
+}
+
+// Time returns the time the KSUID was created, to the second.
+func (k KSUID) Time() time.Time {
+	return time.Unix(int64(k.Timestamp())+ksuidEpoch, 0)
+}
+
+// Payload returns the 16 random bytes of the KSUID.
+func (k KSUID) Payload() []byte {
+	return bytes.Clone(k[4:])
+}
+
+// IsNil returns true if the KSUID is all zeros.
+func (k KSUID) IsNil() bool {
+	return k == KSUID{}
+}
+
+// Compare returns -1, 0 or 1 if k sorts before, the same as, or after other.
+// KSUIDs sort by time, then by payload.
+func (k KSUID) Compare(other KSUID) int {
+	return bytes.Compare(k[:], other[:])
+}
+
+// MarshalText implements encoding.TextMarshaler, which is also used for JSON.
+func (k KSUID) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, which is also used for JSON.
+func (k *KSUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseKSUID(string(text))
+	if err != nil {
+		return err
+	}
+	*k = parsed
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (k KSUID) MarshalBinary() ([]byte, error) {
+	return bytes.Clone(k[:]), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (k *KSUID) UnmarshalBinary(data []byte) error {
+	if len(data) != len(k) {
+		return ErrInvalidKSUID
+	}
+	copy(k[:], data)
+	return nil
+}
+
+// Value implements database/sql/driver.Valuer, storing the KSUID as its string form.
+// The nil KSUID is stored as NULL.
+func (k KSUID) Value() (driver.Value, error) {
+	if k.IsNil() {
+		return nil, nil
+	}
+	return k.String(), nil
+}
+
+// Scan implements database/sql.Scanner, accepting the string form, the 20 raw bytes, or NULL.
+func (k *KSUID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*k = KSUID{}
+		return nil
+	case string:
+		return k.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(k) {
+			return k.UnmarshalBinary(v)
+		}
+		return k.UnmarshalText(v)
+	}
+	return fmt.Errorf("random: can not scan %T into KSUID", src)
+}
@@ -0,0 +1,73 @@
+package random_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veqryn/go-random"
+)
+
+// Example from github.com/segmentio/ksuid
+func TestParseKSUID(t *testing.T) {
+	t.Parallel()
+	k, err := random.ParseKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Timestamp() != 107608047 || k.Time().Unix() != 1507608047 {
+		t.Errorf("Expecting timestamp 107608047; Got: %d", k.Timestamp())
+	}
+	if payload := strings.ToUpper(hex.EncodeToString(k.Payload())); payload != "B5A1CD34B5F99D1154FB6853345C9735" {
+		t.Errorf("Expecting payload B5A1CD34B5F99D1154FB6853345C9735; Got: %s", payload)
+	}
+	if k.String() != "0ujtsYcgvSTl8PAuAdqWYSMnLOv" {
+		t.Errorf("Expecting String to reverse Parse; Got: %s", k.String())
+	}
+
+	if s := (random.KSUID{}).String(); s != "000000000000000000000000000" {
+		t.Errorf("Expecting nil KSUID string; Got: %s", s)
+	}
+	maxKSUID := random.KSUID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if s := maxKSUID.String(); s != "aWgEPTl1tmebfsQzFP4bxwgy80V" {
+		t.Errorf("Expecting max KSUID string; Got: %s", s)
+	}
+	for _, invalid := range []string{"", "0ujtsYcgvSTl8PAuAdqWYSMnLO", "0ujtsYcgvSTl8PAuAdqWYSMnLO-", "aWgEPTl1tmebfsQzFP4bxwgy80W"} {
+		if _, err := random.ParseKSUID(invalid); err != random.ErrInvalidKSUID {
+			t.Errorf("Expecting %q to be invalid; Got: %v", invalid, err)
+		}
+	}
+}
+
+func TestNewKSUID(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	a := random.NewKSUIDWithTime(now.Add(-time.Hour))
+	b := random.NewKSUID()
+	if a.Compare(b) >= 0 || a.String() >= b.String() {
+		t.Errorf("Expecting earlier KSUID to sort first; Got: %s and %s", a, b)
+	}
+	if b.Time().Sub(now) > time.Second || now.Sub(b.Time()) > time.Second {
+		t.Errorf("Expecting time %s; Got: %s", now, b.Time())
+	}
+
+	var decoded struct{ ID random.KSUID }
+	data, _ := json.Marshal(struct{ ID random.KSUID }{b})
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID != b {
+		t.Errorf("Expecting JSON round trip; Got: %s %v", data, err)
+	}
+
+	var scanned random.KSUID
+	value, _ := b.Value()
+	if err := scanned.Scan(value); err != nil || scanned != b {
+		t.Errorf("Expecting SQL round trip; Got: %s %v", scanned, err)
+	}
+	if err := scanned.Scan(b[:]); err != nil || scanned != b {
+		t.Errorf("Expecting scan of raw bytes; Got: %s %v", scanned, err)
+	}
+	if err := scanned.Scan(nil); err != nil || !scanned.IsNil() {
+		t.Errorf("Expecting scan of NULL to give the nil KSUID; Got: %s %v", scanned, err)
+	}
+}
@@ -2,11 +2,11 @@ package random_test
 
 import (
 	"math"
-	"math/rand"
 	"strings"
 	"testing"
 
 	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
 )
 
 func TestPseudoRandomString(t *testing.T) {
@@ -21,7 +21,7 @@ func TestPseudoRandomString(t *testing.T) {
 
 func TestPseudoRandomStringRand(t *testing.T) {
 	t.Parallel()
-	source := rand.New(rand.NewSource(random.SecureRandomNumber(math.MinInt64, math.MaxInt64)))
+	source := randtest.Rand(t)
 	for length := 0; length <= 128; length++ {
 		result := random.PseudoRandomStringRand(source, length)
 		if len(result) != length {
@@ -45,7 +45,7 @@ func TestPseudoRandomStringBytes(t *testing.T) {
 
 func TestPseudoRandomStringBytesRand(t *testing.T) {
 	t.Parallel()
-	source := rand.New(rand.NewSource(random.SecureRandomNumber(math.MinInt64, math.MaxInt64)))
+	source := randtest.Rand(t)
 	for chars := 1; chars <= 256; chars++ {
 		bytes := []byte(strings.Repeat("x", chars))
 		for length := 0; length <= 128; length++ {
@@ -72,7 +72,7 @@ func TestPseudoRandomStringRunes(t *testing.T) {
 
 func TestPseudoRandomStringRunesRand(t *testing.T) {
 	t.Parallel()
-	source := rand.New(rand.NewSource(random.SecureRandomNumber(math.MinInt64, math.MaxInt64)))
+	source := randtest.Rand(t)
 	for chars := 1; chars <= 300; chars++ {
 		runes := []rune(strings.Repeat("x", chars))
 		for length := 0; length <= 128; length++ {
@@ -96,7 +96,7 @@ func TestPseudoRandomBits(t *testing.T) {
 
 func TestPseudoRandomBitsRand(t *testing.T) {
 	t.Parallel()
-	source := rand.New(rand.NewSource(random.SecureRandomNumber(math.MinInt64, math.MaxInt64)))
+	source := randtest.Rand(t)
 	for length := 0; length <= 300; length++ {
 		result := random.PseudoRandomBitsRand(source, length)
 		if len(result) != int(math.Ceil(float64(length)/64.0)) {
@@ -117,7 +117,7 @@ func TestPseudoRandomHex(t *testing.T) {
 
 func TestPseudoRandomHexRand(t *testing.T) {
 	t.Parallel()
-	source := rand.New(rand.NewSource(random.SecureRandomNumber(math.MinInt64, math.MaxInt64)))
+	source := randtest.Rand(t)
 	for length := 0; length <= 300; length++ {
 		result := random.PseudoRandomHexRand(source, length)
 		if len(result) != length {
@@ -138,7 +138,7 @@ func TestPseudoRandomBytes(t *testing.T) {
 
 func TestPseudoRandomBytesRand(t *testing.T) {
 	t.Parallel()
-	source := rand.New(rand.NewSource(random.SecureRandomNumber(math.MinInt64, math.MaxInt64)))
+	source := randtest.Rand(t)
 	for length := 0; length <= 300; length++ {
 		result := random.PseudoRandomBytesRand(source, length)
 		if len(result) != length {
@@ -166,7 +166,7 @@ func TestPseudoRandomInt63(t *testing.T) {
 
 func TestPseudoRandomInt63Rand(t *testing.T) {
 	t.Parallel()
-	source := rand.New(rand.NewSource(random.SecureRandomNumber(math.MinInt64, math.MaxInt64)))
+	source := randtest.Rand(t)
 	increment := int64(math.MaxInt64 / 10)
 	for min := int64(math.MinInt64); min < math.MaxInt64-increment; min += increment {
 		until := min + math.MaxInt64
@@ -0,0 +1,268 @@
+package random
+
+import (
+	"math/rand"
+	"net"
+	"net/netip"
+)
+
+// AddrExclusion is a set of flags for addresses to leave out when choosing a random address.
+type AddrExclusion uint8
+
+const (
+	// ExcludeNetworkAddr leaves out the address with all host bits zero (the network
+	// address in IPv4, and the subnet-router anycast address in IPv6).
+	// Prefixes with fewer than 2 host bits have no network address, per RFC 3021.
+	ExcludeNetworkAddr AddrExclusion = 1 << iota
+
+	// ExcludeBroadcastAddr leaves out the IPv4 address with all host bits one.
+	// Prefixes with fewer than 2 host bits have no broadcast address, per RFC 3021.
+	ExcludeBroadcastAddr
+
+	// ExcludeReservedAddrs leaves out unspecified, "this network", loopback, link-local,
+	// multicast, IPv4 reserved (240.0.0.0/4), and IPv4-mapped IPv6 addresses.
+	ExcludeReservedAddrs
+)
+
+// reservedPrefixes are the prefixes left out by ExcludeReservedAddrs.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/128"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("::ffff:0:0/96"),
+	netip.MustParsePrefix("fe80::/10"),
+	netip.MustParsePrefix("ff00::/8"),
+}
+
+// Ephemeral port range, as recommended by IANA and RFC 6335.
+const (
+	EphemeralPortMin = 49152
+	EphemeralPortMax = 65535
+)
+
+// SecureRandomAddr uses crypto/rand to return a random IPv4 or IPv6 address within the prefix,
+// leaving out any excluded addresses.
+// If the prefix is invalid, or every address in it is excluded, this will panic.
+func SecureRandomAddr(prefix netip.Prefix, exclude AddrExclusion) netip.Addr {
+	return randomAddrBase(SecureRandomBytes, prefix, exclude)
+}
+
+// PseudoRandomAddr uses math/rand to return a random IPv4 or IPv6 address within the prefix,
+// leaving out any excluded addresses.
+// If the prefix is invalid, or every address in it is excluded, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomAddr(prefix netip.Prefix, exclude AddrExclusion) netip.Addr {
+	return randomAddrBase(PseudoRandomBytes, prefix, exclude)
+}
+
+// PseudoRandomAddrRand uses math/rand to return a random IPv4 or IPv6 address within the prefix,
+// leaving out any excluded addresses.
+// If the prefix is invalid, or every address in it is excluded, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomAddrRand(rand *rand.Rand, prefix netip.Prefix, exclude AddrExclusion) netip.Addr {
+	return randomAddrBase(func(length int) []byte { return PseudoRandomBytesRand(rand, length) }, prefix, exclude)
+}
+
+// randomAddrBase returns a random address within the prefix, leaving out any excluded addresses.
+// Excluded addresses are rejected and regenerated, keeping the remaining addresses equally likely.
+func randomAddrBase(randomBytes func(int) []byte, prefix netip.Prefix, exclude AddrExclusion) netip.Addr {
+	if !prefix.IsValid() {
+		panic("random: prefix is not valid")
+	}
+	prefix = prefix.Masked()
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+
+	// Prefixes with fewer than 2 host bits have no network or broadcast address, per RFC 3021
+	if hostBits < 2 {
+		exclude &^= ExcludeNetworkAddr | ExcludeBroadcastAddr
+	}
+	if !prefix.Addr().Is4() {
+		exclude &^= ExcludeBroadcastAddr
+	}
+	if !hasAllowedAddr(prefix, exclude) {
+		panic("random: every address in the prefix is excluded")
+	}
+
+	for {
+		addr := randomHostAddr(randomBytes, prefix)
+		if exclude&ExcludeNetworkAddr != 0 && addr == prefix.Addr() {
+			continue
+		}
+		if exclude&ExcludeBroadcastAddr != 0 && addr == lastAddr(prefix) {
+			continue
+		}
+		if exclude&ExcludeReservedAddrs != 0 && isReservedAddr(addr) {
+			continue
+		}
+		return addr
+	}
+}
+
+// hasAllowedAddr returns true if at least one address in the masked prefix is not excluded,
+// so that the rejection loop of randomAddrBase will end.
+func hasAllowedAddr(prefix netip.Prefix, exclude AddrExclusion) bool {
+	count := min(1<<min(prefix.Addr().BitLen()-prefix.Bits(), 2), 3)
+	if exclude&ExcludeReservedAddrs != 0 {
+		count = unreservedAddrs(prefix)
+	}
+	if exclude&ExcludeNetworkAddr != 0 && !(exclude&ExcludeReservedAddrs != 0 && isReservedAddr(prefix.Addr())) {
+		count--
+	}
+	if exclude&ExcludeBroadcastAddr != 0 && !(exclude&ExcludeReservedAddrs != 0 && isReservedAddr(lastAddr(prefix))) {
+		count--
+	}
+	return count > 0
+}
+
+// unreservedAddrs returns how many addresses in the masked prefix are not in any of the
+// reservedPrefixes, up to a maximum of 3, which is enough to know whether any are left
+// after also excluding the network and broadcast addresses.
+// Prefixes are split in half until each half is either entirely reserved, or contains no
+// reserved prefixes, so that reserved prefixes that only together cover it are counted correctly.
+func unreservedAddrs(prefix netip.Prefix) int {
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	split := false
+	for _, reserved := range reservedPrefixes {
+		if reserved.Addr().BitLen() != prefix.Addr().BitLen() {
+			continue
+		}
+		if reserved.Bits() <= prefix.Bits() && reserved.Contains(prefix.Addr()) {
+			return 0
+		}
+		if prefix.Contains(reserved.Addr()) {
+			split = true
+		}
+	}
+	if !split {
+		return min(1<<min(hostBits, 2), 3)
+	}
+	lower := netip.PrefixFrom(prefix.Addr(), prefix.Bits()+1)
+	upper := netip.PrefixFrom(lastAddr(lower).Next(), prefix.Bits()+1)
+	return min(unreservedAddrs(lower)+unreservedAddrs(upper), 3)
+}
+
+// randomHostAddr returns the masked prefix's address with its host bits randomized.
+func randomHostAddr(randomBytes func(int) []byte, prefix netip.Prefix) netip.Addr {
+	addr := prefix.Addr().AsSlice()
+	random := randomBytes(len(addr))
+	for i := range addr {
+		// networkBits is how many of this byte's bits belong to the prefix
+		networkBits := min(max(prefix.Bits()-8*i, 0), 8)
+		hostMask := byte(0xFF >> networkBits)
+		addr[i] = addr[i]&^hostMask | random[i]&hostMask
+	}
+	result, _ := netip.AddrFromSlice(addr)
+	return result
+}
+
+// lastAddr returns the address in the masked prefix with all host bits set.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Addr().AsSlice()
+	for i := range addr {
+		networkBits := min(max(prefix.Bits()-8*i, 0), 8)
+		addr[i] |= byte(0xFF >> networkBits)
+	}
+	result, _ := netip.AddrFromSlice(addr)
+	return result
+}
+
+// isReservedAddr returns true if the address is in any of the reservedPrefixes.
+func isReservedAddr(addr netip.Addr) bool {
+	for _, reserved := range reservedPrefixes {
+		if reserved.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// SecureRandomSubnet uses crypto/rand to return a random prefix with the given number
+// of bits inside the parent prefix. Every subnet is equally likely.
+// If the parent is invalid, or bits is less than the parent's bits or more than the address length, this will panic.
+func SecureRandomSubnet(parent netip.Prefix, bits int) netip.Prefix {
+	return randomSubnetBase(SecureRandomBytes, parent, bits)
+}
+
+// PseudoRandomSubnet uses math/rand to return a random prefix with the given number
+// of bits inside the parent prefix. Every subnet is equally likely.
+// If the parent is invalid, or bits is less than the parent's bits or more than the address length, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomSubnet(parent netip.Prefix, bits int) netip.Prefix {
+	return randomSubnetBase(PseudoRandomBytes, parent, bits)
+}
+
+// PseudoRandomSubnetRand uses math/rand to return a random prefix with the given number
+// of bits inside the parent prefix. Every subnet is equally likely.
+// If the parent is invalid, or bits is less than the parent's bits or more than the address length, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomSubnetRand(rand *rand.Rand, parent netip.Prefix, bits int) netip.Prefix {
+	return randomSubnetBase(func(length int) []byte { return PseudoRandomBytesRand(rand, length) }, parent, bits)
+}
+
+// randomSubnetBase returns a random prefix with the given number of bits inside the parent prefix.
+func randomSubnetBase(randomBytes func(int) []byte, parent netip.Prefix, bits int) netip.Prefix {
+	if !parent.IsValid() {
+		panic("random: parent prefix is not valid")
+	}
+	if bits < parent.Bits() || bits > parent.Addr().BitLen() {
+		panic("random: subnet bits must be between the parent's bits and the address length")
+	}
+	// Each subnet contains the same number of addresses, so a random address picks a random subnet
+	subnet, _ := randomHostAddr(randomBytes, parent.Masked()).Prefix(bits)
+	return subnet
+}
+
+// SecureRandomMAC uses crypto/rand to return a random locally administered, unicast MAC-48 address.
+func SecureRandomMAC() net.HardwareAddr {
+	return randomMACBase(SecureRandomBytes(6))
+}
+
+// PseudoRandomMAC uses math/rand to return a random locally administered, unicast MAC-48 address.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomMAC() net.HardwareAddr {
+	return randomMACBase(PseudoRandomBytes(6))
+}
+
+// PseudoRandomMACRand uses math/rand to return a random locally administered, unicast MAC-48 address.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomMACRand(rand *rand.Rand) net.HardwareAddr {
+	return randomMACBase(PseudoRandomBytesRand(rand, 6))
+}
+
+// randomMACBase sets the locally administered bit and clears the multicast bit of the random bytes.
+func randomMACBase(randomBytes []byte) net.HardwareAddr {
+	randomBytes[0] = randomBytes[0]&^0x01 | 0x02
+	return net.HardwareAddr(randomBytes)
+}
+
+// SecureRandomPort uses crypto/rand to return a random port in the ephemeral
+// range, [EphemeralPortMin, EphemeralPortMax].
+func SecureRandomPort() uint16 {
+	return uint16(SecureRandomNumber(EphemeralPortMin, EphemeralPortMax+1))
+}
+
+// PseudoRandomPort uses math/rand to return a random port in the ephemeral
+// range, [EphemeralPortMin, EphemeralPortMax].
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomPort() uint16 {
+	return uint16(PseudoRandomInt63(EphemeralPortMin, EphemeralPortMax+1))
+}
+
+// PseudoRandomPortRand uses math/rand to return a random port in the ephemeral
+// range, [EphemeralPortMin, EphemeralPortMax].
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomPortRand(rand *rand.Rand) uint16 {
+	return uint16(PseudoRandomInt63Rand(rand, EphemeralPortMin, EphemeralPortMax+1))
+}
@@ -0,0 +1,126 @@
+package random_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestSecureRandomAddr(t *testing.T) {
+	t.Parallel()
+	for _, s := range []string{"10.1.2.0/24", "192.168.0.0/16", "2001:db8::/32", "2001:db8::/126", "10.0.0.7/32"} {
+		prefix := netip.MustParsePrefix(s)
+		for i := 0; i < 200; i++ {
+			addr := random.SecureRandomAddr(prefix, 0)
+			if !prefix.Contains(addr) {
+				t.Errorf("Expecting address in %s; Got: %s", prefix, addr)
+			}
+		}
+	}
+}
+
+func TestPseudoRandomAddrRand(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	prefix := netip.MustParsePrefix("10.1.2.4/30")
+	seen := map[netip.Addr]bool{}
+	for i := 0; i < 1000; i++ {
+		seen[random.PseudoRandomAddrRand(source, prefix, random.ExcludeNetworkAddr|random.ExcludeBroadcastAddr)] = true
+	}
+	if len(seen) != 2 || !seen[netip.MustParseAddr("10.1.2.5")] || !seen[netip.MustParseAddr("10.1.2.6")] {
+		t.Errorf("Expecting only the 2 host addresses; Got: %v", seen)
+	}
+
+	// A /31 has no network or broadcast address
+	prefix = netip.MustParsePrefix("10.1.2.4/31")
+	seen = map[netip.Addr]bool{}
+	for i := 0; i < 1000; i++ {
+		seen[random.PseudoRandomAddrRand(source, prefix, random.ExcludeNetworkAddr|random.ExcludeBroadcastAddr)] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("Expecting both /31 addresses; Got: %v", seen)
+	}
+
+	all := netip.MustParsePrefix("0.0.0.0/0")
+	for i := 0; i < 1000; i++ {
+		addr := random.PseudoRandomAddrRand(source, all, random.ExcludeReservedAddrs)
+		if addr.IsLoopback() || addr.IsMulticast() || addr.IsLinkLocalUnicast() || addr.IsUnspecified() {
+			t.Errorf("Expecting no reserved addresses; Got: %s", addr)
+		}
+	}
+}
+
+func TestRandomAddrAllReserved(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Error("Expecting panic when every address is reserved")
+		}
+	}()
+	random.PseudoRandomAddr(netip.MustParsePrefix("127.0.0.0/16"), random.ExcludeReservedAddrs)
+}
+
+func TestRandomAddrAllExcludedByUnion(t *testing.T) {
+	t.Parallel()
+	// Prefixes that are only covered by several reserved prefixes together
+	for _, prefix := range []string{"::/127", "224.0.0.0/3"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expecting panic when every address in %s is reserved", prefix)
+				}
+			}()
+			random.PseudoRandomAddr(netip.MustParsePrefix(prefix), random.ExcludeReservedAddrs)
+		}()
+	}
+
+	// The network address :: is already reserved, so only ::2 and ::3 are left
+	source := randtest.Rand(t)
+	for i := 0; i < 100; i++ {
+		addr := random.PseudoRandomAddrRand(source, netip.MustParsePrefix("::/126"), random.ExcludeReservedAddrs|random.ExcludeNetworkAddr)
+		if addr != netip.MustParseAddr("::2") && addr != netip.MustParseAddr("::3") {
+			t.Fatalf("Expecting ::2 or ::3; Got: %s", addr)
+		}
+	}
+}
+
+func TestSecureRandomSubnet(t *testing.T) {
+	t.Parallel()
+	parent := netip.MustParsePrefix("10.0.0.0/8")
+	for i := 0; i < 200; i++ {
+		subnet := random.SecureRandomSubnet(parent, 24)
+		if subnet.Bits() != 24 || !parent.Contains(subnet.Addr()) || subnet != subnet.Masked() {
+			t.Errorf("Expecting masked /24 in %s; Got: %s", parent, subnet)
+		}
+	}
+	parent = netip.MustParsePrefix("2001:db8::/32")
+	if subnet := random.PseudoRandomSubnet(parent, 64); subnet.Bits() != 64 || !parent.Contains(subnet.Addr()) {
+		t.Errorf("Expecting /64 in %s; Got: %s", parent, subnet)
+	}
+}
+
+func TestSecureRandomMAC(t *testing.T) {
+	t.Parallel()
+	for i := 0; i < 200; i++ {
+		mac := random.SecureRandomMAC()
+		if len(mac) != 6 || mac[0]&0x01 != 0 || mac[0]&0x02 == 0 {
+			t.Errorf("Expecting locally administered unicast MAC; Got: %s", mac)
+		}
+	}
+}
+
+func TestPseudoRandomPortRand(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	for i := 0; i < 1000; i++ {
+		port := random.PseudoRandomPortRand(source)
+		if port < random.EphemeralPortMin {
+			t.Errorf("Expecting ephemeral port; Got: %d", port)
+		}
+	}
+	if port := random.SecureRandomPort(); port < random.EphemeralPortMin {
+		t.Errorf("Expecting ephemeral port; Got: %d", port)
+	}
+}
@@ -0,0 +1,252 @@
+// Package otp provisions and verifies one-time passwords for multi-factor authentication:
+// HOTP (RFC 4226) and TOTP (RFC 6238) secrets, otpauth:// provisioning URIs for authenticator apps,
+// code verification with a tolerance window, and one-time recovery codes with a hashed storage form.
+// All random material comes from crypto/rand via the random package.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/veqryn/go-random"
+)
+
+// Algorithm is the HMAC hash function used to compute codes.
+type Algorithm int
+
+// Algorithms supported by authenticator apps. SHA1 is the default, and the only one supported by some apps.
+const (
+	SHA1 Algorithm = iota
+	SHA256
+	SHA512
+)
+
+// String returns the algorithm's name, as used in provisioning URIs.
+func (a Algorithm) String() string {
+	switch a {
+	case SHA1:
+		return "SHA1"
+	case SHA256:
+		return "SHA256"
+	case SHA512:
+		return "SHA512"
+	}
+	return "Algorithm(" + strconv.Itoa(int(a)) + ")"
+}
+
+// hash returns the hash function for the algorithm.
+func (a Algorithm) hash() func() hash.Hash {
+	switch a {
+	case SHA1:
+		return sha1.New
+	case SHA256:
+		return sha256.New
+	case SHA512:
+		return sha512.New
+	}
+	panic("otp: unknown algorithm " + a.String())
+}
+
+// Defaults used when a Key's fields are zero.
+const (
+	DefaultSecretSize = 20 // 160 bits, as recommended by RFC 4226
+	DefaultDigits     = 6
+	DefaultPeriod     = 30 * time.Second
+)
+
+// ErrInvalidSecret is returned when a Key's secret is not valid base32.
+var ErrInvalidSecret = errors.New("otp: secret is not valid base32")
+
+// secretEncoding is the base32 encoding authenticator apps expect, without padding.
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret uses crypto/rand to return a random base32 encoded secret of size bytes.
+// If size is not positive, this will panic.
+func NewSecret(size int) string {
+	if size <= 0 {
+		panic("otp: secret size must be greater than zero")
+	}
+	b := random.SecureRandomBytes(size)
+	secret := secretEncoding.EncodeToString(b)
+	clear(b)
+	return secret
+}
+
+// Key is an HOTP or TOTP key, along with the settings an authenticator app needs.
+type Key struct {
+	// Issuer is the provider or service the account is for, such as "Example Corp".
+	Issuer string
+
+	// Account is the user's account name, such as their email address.
+	Account string
+
+	// Secret is the shared secret, base32 encoded. Lower case, spaces and padding are tolerated.
+	Secret string
+
+	// Algorithm is the hash function. The default is SHA1.
+	Algorithm Algorithm
+
+	// Digits is the length of codes, usually 6 or 8. If zero, the default is 6.
+	Digits int
+
+	// Period is how long each TOTP code is valid for. If zero, the default is 30 seconds.
+	// Provisioning URIs can only express whole seconds, so it must be a positive whole number
+	// of seconds, otherwise URI, TOTP and VerifyTOTP will panic.
+	Period time.Duration
+}
+
+// GenerateKey returns a Key with a new random secret of DefaultSecretSize, and the default settings.
+func GenerateKey(issuer, account string) Key {
+	return Key{Issuer: issuer, Account: account, Secret: NewSecret(DefaultSecretSize)}
+}
+
+// digits returns the number of digits, or the default.
+func (k Key) digits() int {
+	if k.Digits == 0 {
+		return DefaultDigits
+	}
+	return k.Digits
+}
+
+// period returns the TOTP period, or the default.
+// If the period is not a positive whole number of seconds, this will panic.
+func (k Key) period() time.Duration {
+	if k.Period == 0 {
+		return DefaultPeriod
+	}
+	if k.Period < time.Second || k.Period%time.Second != 0 {
+		panic("otp: Period must be a positive whole number of seconds")
+	}
+	return k.Period
+}
+
+// secret returns the decoded secret.
+func (k Key) secret() ([]byte, error) {
+	s := strings.ToUpper(strings.ReplaceAll(k.Secret, " ", ""))
+	secret, err := secretEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil || len(secret) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return secret, nil
+}
+
+// URI returns the otpauth:// TOTP provisioning URI, usually shown to the user as a QR code.
+// If the Period is invalid, this will panic.
+func (k Key) URI() string {
+	return k.uri("totp", url.Values{"period": {strconv.Itoa(int(k.period() / time.Second))}})
+}
+
+// HOTPURI returns the otpauth:// HOTP provisioning URI, starting at the counter.
+func (k Key) HOTPURI(counter uint64) string {
+	return k.uri("hotp", url.Values{"counter": {strconv.FormatUint(counter, 10)}})
+}
+
+// uri returns an otpauth:// provisioning URI of the type, with the extra parameters.
+func (k Key) uri(typ string, params url.Values) string {
+	label := k.Account
+	if k.Issuer != "" {
+		label = k.Issuer + ":" + k.Account
+		params.Set("issuer", k.Issuer)
+	}
+	params.Set("secret", strings.TrimRight(strings.ToUpper(strings.ReplaceAll(k.Secret, " ", "")), "="))
+	params.Set("algorithm", k.Algorithm.String())
+	params.Set("digits", strconv.Itoa(k.digits()))
+	u := url.URL{Scheme: "otpauth", Host: typ, Path: "/" + label, RawQuery: params.Encode()}
+	return u.String()
+}
+
+// HOTP returns the RFC 4226 code for the counter.
+// If the secret is invalid, it returns ErrInvalidSecret.
+func (k Key) HOTP(counter uint64) (string, error) {
+	secret, err := k.secret()
+	if err != nil {
+		return "", err
+	}
+	defer clear(secret)
+	return hotp(k.Algorithm, secret, counter, k.digits()), nil
+}
+
+// TOTP returns the RFC 6238 code for the time.
+// If the secret is invalid, it returns ErrInvalidSecret.
+// If the Period is invalid, this will panic.
+func (k Key) TOTP(t time.Time) (string, error) {
+	return k.HOTP(k.step(t))
+}
+
+// step returns the TOTP time step containing t.
+func (k Key) step(t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(k.period()/time.Second)
+}
+
+// VerifyHOTP returns true if the code matches the counter or one of the next window counters,
+// along with the counter after the one that matched, which should be stored as the next counter,
+// so that the code can not be used again and the counters are resynchronized.
+// If the secret is invalid, it returns ErrInvalidSecret.
+func (k Key) VerifyHOTP(code string, counter uint64, window int) (next uint64, ok bool, err error) {
+	secret, err := k.secret()
+	if err != nil {
+		return 0, false, err
+	}
+	defer clear(secret)
+	for i := uint64(0); i <= uint64(max(window, 0)); i++ {
+		if equalCodes(hotp(k.Algorithm, secret, counter+i, k.digits()), code) {
+			return counter + i + 1, true, nil
+		}
+	}
+	return counter, false, nil
+}
+
+// VerifyTOTP returns true if the code matches the time step containing t, or up to window steps
+// before or after it, to tolerate clock drift and slow typing. It also returns the step that matched;
+// store it and reject later codes for the same or an earlier step, so that codes can not be replayed.
+// If the secret is invalid, it returns ErrInvalidSecret.
+// If the Period is invalid, this will panic.
+func (k Key) VerifyTOTP(code string, t time.Time, window int) (step uint64, ok bool, err error) {
+	secret, err := k.secret()
+	if err != nil {
+		return 0, false, err
+	}
+	defer clear(secret)
+	current := k.step(t)
+	for i := -max(window, 0); i <= max(window, 0); i++ {
+		step = current + uint64(i)
+		if equalCodes(hotp(k.Algorithm, secret, step, k.digits()), code) {
+			return step, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// equalCodes compares codes in constant time.
+func equalCodes(expected, code string) bool {
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.TrimSpace(code))) == 1
+}
+
+// powersOfTen are used to truncate codes to their number of digits.
+var powersOfTen = [...]uint64{1, 10, 100, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10}
+
+// hotp implements the HOTP algorithm of RFC 4226, with dynamic truncation.
+// If digits is not between 1 and 10, this will panic.
+func hotp(algorithm Algorithm, secret []byte, counter uint64, digits int) string {
+	if digits < 1 || digits >= len(powersOfTen) {
+		panic("otp: digits must be between 1 and 10")
+	}
+	mac := hmac.New(algorithm.hash(), secret)
+	mac.Write(binary.BigEndian.AppendUint64(nil, counter))
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	code := uint64(binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff)
+	s := strconv.FormatUint(code%powersOfTen[digits], 10)
+	return strings.Repeat("0", digits-len(s)) + s
+}
@@ -0,0 +1,159 @@
+package otp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veqryn/go-random/otp"
+)
+
+func b32(s string) string {
+	return base32.StdEncoding.EncodeToString([]byte(s))
+}
+
+// Test vectors from RFC 4226 Appendix D
+func TestHOTP(t *testing.T) {
+	t.Parallel()
+	key := otp.Key{Secret: b32("12345678901234567890")}
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, e := range expected {
+		code, err := key.HOTP(uint64(counter))
+		if err != nil || code != e {
+			t.Errorf("Expecting HOTP %s for counter %d; Got: %s %v", e, counter, code, err)
+		}
+	}
+
+	next, ok, err := key.VerifyHOTP("969429", 1, 3)
+	if err != nil || !ok || next != 4 {
+		t.Errorf("Expecting code within window to verify and resync to 4; Got: %d %t %v", next, ok, err)
+	}
+	if _, ok, _ = key.VerifyHOTP("969429", 4, 3); ok {
+		t.Error("Expecting used code to not verify")
+	}
+}
+
+// Test vectors from RFC 6238 Appendix B
+func TestTOTP(t *testing.T) {
+	t.Parallel()
+	keys := map[otp.Algorithm]otp.Key{
+		otp.SHA1:   {Secret: b32("12345678901234567890"), Digits: 8},
+		otp.SHA256: {Secret: b32("12345678901234567890123456789012"), Digits: 8, Algorithm: otp.SHA256},
+		otp.SHA512: {Secret: b32("1234567890123456789012345678901234567890123456789012345678901234"), Digits: 8, Algorithm: otp.SHA512},
+	}
+	tests := []struct {
+		unix     int64
+		expected map[otp.Algorithm]string
+	}{
+		{59, map[otp.Algorithm]string{otp.SHA1: "94287082", otp.SHA256: "46119246", otp.SHA512: "90693936"}},
+		{1111111109, map[otp.Algorithm]string{otp.SHA1: "07081804", otp.SHA256: "68084774", otp.SHA512: "25091201"}},
+		{1111111111, map[otp.Algorithm]string{otp.SHA1: "14050471", otp.SHA256: "67062674", otp.SHA512: "99943326"}},
+		{1234567890, map[otp.Algorithm]string{otp.SHA1: "89005924", otp.SHA256: "91819424", otp.SHA512: "93441116"}},
+		{2000000000, map[otp.Algorithm]string{otp.SHA1: "69279037", otp.SHA256: "90698825", otp.SHA512: "38618901"}},
+		{20000000000, map[otp.Algorithm]string{otp.SHA1: "65353130", otp.SHA256: "77737706", otp.SHA512: "47863826"}},
+	}
+	for _, tc := range tests {
+		for alg, e := range tc.expected {
+			code, err := keys[alg].TOTP(time.Unix(tc.unix, 0))
+			if err != nil || code != e {
+				t.Errorf("Expecting %s TOTP %s at %d; Got: %s %v", alg, e, tc.unix, code, err)
+			}
+		}
+	}
+
+	key := keys[otp.SHA1]
+	now := time.Unix(1111111109, 0)
+	if _, ok, _ := key.VerifyTOTP("07081804", now.Add(30*time.Second), 1); !ok {
+		t.Error("Expecting previous step code to verify within window")
+	}
+	if _, ok, _ := key.VerifyTOTP("07081804", now.Add(90*time.Second), 1); ok {
+		t.Error("Expecting code outside window to not verify")
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	t.Parallel()
+	key := otp.GenerateKey("Example Corp", "alice@example.com")
+	if len(key.Secret) != 32 {
+		t.Errorf("Expecting 32 base32 characters for a 20 byte secret; Got: %q", key.Secret)
+	}
+	code, err := key.TOTP(time.Now())
+	if err != nil || len(code) != 6 {
+		t.Fatalf("Expecting a 6 digit code; Got: %q %v", code, err)
+	}
+	if _, ok, err := key.VerifyTOTP(code, time.Now(), 1); !ok || err != nil {
+		t.Errorf("Expecting generated code to verify; Got: %t %v", ok, err)
+	}
+
+	u, err := url.Parse(key.URI())
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Example Corp:alice@example.com" ||
+		q.Get("secret") != key.Secret || q.Get("issuer") != "Example Corp" || q.Get("period") != "30" ||
+		q.Get("digits") != "6" || q.Get("algorithm") != "SHA1" {
+		t.Errorf("Expecting valid provisioning URI; Got: %s", key.URI())
+	}
+	if !strings.HasPrefix(key.HOTPURI(5), "otpauth://hotp/") || !strings.Contains(key.HOTPURI(5), "counter=5") {
+		t.Errorf("Expecting valid HOTP provisioning URI; Got: %s", key.HOTPURI(5))
+	}
+
+	if _, err = (otp.Key{Secret: "not base32!"}).TOTP(time.Now()); err != otp.ErrInvalidSecret {
+		t.Errorf("Expecting ErrInvalidSecret; Got: %v", err)
+	}
+}
+
+func TestInvalidPeriod(t *testing.T) {
+	t.Parallel()
+	for _, period := range []time.Duration{500 * time.Millisecond, 1500 * time.Millisecond, -time.Minute} {
+		key := otp.GenerateKey("Example Corp", "alice@example.com")
+		key.Period = period
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expecting panic for period %v", period)
+				}
+			}()
+			_, _ = key.TOTP(time.Now())
+		}()
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	t.Parallel()
+	codes, hashes := otp.NewRecoveryCodes(5)
+	if len(codes) != 5 || len(hashes) != 5 {
+		t.Fatalf("Expecting 5 codes and hashes; Got: %d and %d", len(codes), len(hashes))
+	}
+	for i, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("Expecting code like XXXXX-XXXXX; Got: %s", code)
+		}
+		if strings.Contains(hashes[i], code) {
+			t.Errorf("Expecting hash to not contain the code; Got: %s", hashes[i])
+		}
+		if !otp.VerifyRecoveryCode(code, hashes[i]) {
+			t.Errorf("Expecting code %s to verify", code)
+		}
+		// Typed sloppily
+		if !otp.VerifyRecoveryCode(" "+strings.ToLower(strings.ReplaceAll(code, "-", ""))+" ", hashes[i]) {
+			t.Errorf("Expecting lower case code without dash %s to verify", code)
+		}
+		if otp.VerifyRecoveryCode(code, hashes[(i+1)%len(hashes)]) {
+			t.Errorf("Expecting code %s to not verify against another hash", code)
+		}
+	}
+	if otp.HashRecoveryCode(codes[0]) == hashes[0] {
+		t.Error("Expecting hashes to be salted")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expecting panic when hashing a malformed code")
+		}
+	}()
+	otp.HashRecoveryCode("not a code")
+}
@@ -0,0 +1,93 @@
+package otp
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/veqryn/go-random"
+)
+
+// RecoveryCodeFormat is the format of recovery codes, such as "7K3QD-XM9PA":
+// 10 Crockford base32 characters (50 bits), which are easy to read aloud and type,
+// and are checked against the default blocklist.
+var RecoveryCodeFormat = random.CodeFormat{
+	AvailableCharBytes: random.Crockford32Bytes,
+	Length:             10,
+	GroupSize:          5,
+	Separator:          "-",
+	Blocklist:          random.DefaultBlocklist,
+}
+
+// recoveryHashIterations is the PBKDF2 iteration count for recovery codes.
+// Codes have far more entropy than passwords, so fewer iterations than for passwords are needed,
+// which keeps checking a code against all of a user's stored hashes fast.
+const recoveryHashIterations = 10000
+
+// recoveryHashPrefix identifies the hashing scheme of stored recovery codes.
+const recoveryHashPrefix = "pbkdf2-sha256"
+
+// NewRecoveryCodes uses crypto/rand to return count one-time recovery codes, to show to the user once,
+// along with their hashed forms, to store instead of the codes.
+func NewRecoveryCodes(count int) (codes, hashes []string) {
+	codes = make([]string, count)
+	hashes = make([]string, count)
+	for i := range codes {
+		codes[i] = random.SecureFormattedCode(RecoveryCodeFormat)
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+	return codes, hashes
+}
+
+// HashRecoveryCode returns the storage form of a recovery code: a salted PBKDF2-SHA256 hash,
+// encoded as "pbkdf2-sha256$<iterations>$<salt>$<hash>".
+// If the code is not in RecoveryCodeFormat, this will panic.
+func HashRecoveryCode(code string) string {
+	normalized, ok := random.NormalizeCode(RecoveryCodeFormat, code)
+	if !ok {
+		panic("otp: code is not a recovery code")
+	}
+	salt := random.SecureRandomSalt()
+	return strings.Join([]string{
+		recoveryHashPrefix,
+		strconv.Itoa(recoveryHashIterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(recoveryHash(normalized, salt, recoveryHashIterations)),
+	}, "$")
+}
+
+// VerifyRecoveryCode returns true if the code, as typed by the user, matches the hashed form.
+// Case, whitespace, dashes, and commonly confused characters are tolerated.
+// Remove the hash once it has been used, so that each code only works once.
+func VerifyRecoveryCode(code, hashed string) bool {
+	normalized, ok := random.NormalizeCode(RecoveryCodeFormat, code)
+	if !ok {
+		return false
+	}
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 4 || parts[0] != recoveryHashPrefix {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err1 := base64.RawStdEncoding.DecodeString(parts[2])
+	expected, err2 := base64.RawStdEncoding.DecodeString(parts[3])
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(recoveryHash(normalized, salt, iterations), expected) == 1
+}
+
+// recoveryHash returns the PBKDF2-SHA256 hash of the normalized code.
+func recoveryHash(normalized string, salt []byte, iterations int) []byte {
+	key, err := pbkdf2.Key(sha256.New, normalized, salt, iterations, sha256.Size)
+	if err != nil {
+		panic("otp: " + err.Error()) // Impossible
+	}
+	return key
+}
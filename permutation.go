@@ -0,0 +1,114 @@
+package random
+
+import "math/bits"
+
+// Permutation is a keyed pseudo-random permutation of the integers [0, n): every integer is mapped
+// to a different integer in the same range, and only someone with the key can compute the mapping
+// or its inverse. It can turn sequential database IDs into public IDs that are collision-free
+// without any checks, and that do not reveal how many IDs exist, then back again.
+// It is a balanced Feistel network with AES as the round function, and cycle-walking to stay
+// within [0, n). It is not a standardized format-preserving encryption mode such as FF1.
+// It is thread-safe.
+type Permutation struct {
+	f *feistel
+}
+
+// NewPermutation returns the permutation of [0, n) for the 16, 24 or 32 byte key,
+// such as one from SecureRandomAES128Key. The same key and n always give the same permutation.
+// If n is zero, or the key is the wrong length, this will panic.
+func NewPermutation(key []byte, n uint64) *Permutation {
+	return &Permutation{f: newFeistel(key, n)}
+}
+
+// Size returns n, the size of the range being permuted.
+func (p *Permutation) Size() uint64 {
+	return p.f.n
+}
+
+// Permute returns the position of x in the permutation.
+// If x is not less than n, this will panic.
+func (p *Permutation) Permute(x uint64) uint64 {
+	if x >= p.f.n {
+		panic("random: x must be less than the permutation size")
+	}
+	return p.f.encrypt(x)
+}
+
+// Invert returns the x that Permute maps to y.
+// If y is not less than n, this will panic.
+func (p *Permutation) Invert(y uint64) uint64 {
+	if y >= p.f.n {
+		panic("random: y must be less than the permutation size")
+	}
+	return p.f.decrypt(y)
+}
+
+// Encode permutes x, and returns it written with the available character bytes as digits,
+// such as AlphaNumericBytes or Base64URLBytes. Every result has the same length, which is the
+// fewest characters that can write n - 1, so that the length does not reveal anything about x.
+// To use every string of a given length, make n a power of the number of available characters.
+// If x is not less than n, or the available character bytes are not unique or their number is not between 2 and 256, this will panic.
+func (p *Permutation) Encode(x uint64, availableCharBytes []byte) string {
+	checkPermutationChars(availableCharBytes)
+	y := p.Permute(x)
+	base := uint64(len(availableCharBytes))
+	result := make([]byte, p.width(base))
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = availableCharBytes[y%base]
+		y /= base
+	}
+	return string(result)
+}
+
+// Decode reverses Encode, returning x, and whether s is a valid encoding.
+// If the available character bytes are not unique or their number is not between 2 and 256, this will panic.
+func (p *Permutation) Decode(s string, availableCharBytes []byte) (uint64, bool) {
+	indices := checkPermutationChars(availableCharBytes)
+	base := uint64(len(availableCharBytes))
+	if len(s) != p.width(base) {
+		return 0, false
+	}
+	y := uint64(0)
+	for i := 0; i < len(s); i++ {
+		idx := indices[s[i]]
+		if idx < 0 {
+			return 0, false
+		}
+		// Strings of the right width can only overflow if their value is at least n
+		hi, lo := bits.Mul64(y, base)
+		var carry uint64
+		y, carry = bits.Add64(lo, uint64(idx), 0)
+		if hi != 0 || carry != 0 {
+			return 0, false
+		}
+	}
+	if y >= p.f.n {
+		return 0, false
+	}
+	return p.f.decrypt(y), true
+}
+
+// width returns how many digits in base are needed to write n - 1.
+func (p *Permutation) width(base uint64) int {
+	width := 1
+	for largest := p.f.n - 1; largest >= base; largest /= base {
+		width++
+	}
+	return width
+}
+
+// checkPermutationChars returns a lookup table from each byte to its index in the available
+// characters, or -1 if it is not available.
+// If the characters are not unique, or their number is not between 2 and 256, this will panic.
+func checkPermutationChars(availableCharBytes []byte) [256]int16 {
+	if len(availableCharBytes) < 2 {
+		panic("random: availableCharBytes must have at least 2 bytes")
+	}
+	indices := codeIndices(availableCharBytes)
+	for i, c := range availableCharBytes {
+		if indices[c] != int16(i) {
+			panic("random: availableCharBytes must not contain duplicates")
+		}
+	}
+	return indices
+}
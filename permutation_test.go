@@ -0,0 +1,89 @@
+package random_test
+
+import (
+	"testing"
+
+	"github.com/veqryn/go-random"
+)
+
+func TestPermutation(t *testing.T) {
+	t.Parallel()
+	key := random.SecureRandomAES128Key()
+	for _, n := range []uint64{1, 2, 10, 1000, 4096} {
+		p := random.NewPermutation(key, n)
+		seen := make(map[uint64]bool, n)
+		for x := uint64(0); x < n; x++ {
+			y := p.Permute(x)
+			if y >= n || seen[y] {
+				t.Fatalf("Expecting a permutation of [0, %d); Got: %d for %d", n, y, x)
+			}
+			seen[y] = true
+			if back := p.Invert(y); back != x {
+				t.Errorf("Expecting Invert to reverse Permute; Got: %d for %d", back, x)
+			}
+		}
+	}
+
+	// The same key gives the same permutation, and a different key a different one
+	a, b := random.NewPermutation(key, 1<<40), random.NewPermutation(key, 1<<40)
+	c := random.NewPermutation(random.SecureRandomAES256Key(), 1<<40)
+	same := 0
+	for x := uint64(0); x < 100; x++ {
+		if a.Permute(x) != b.Permute(x) {
+			t.Errorf("Expecting the same key to give the same permutation")
+		}
+		if a.Permute(x) == c.Permute(x) {
+			same++
+		}
+	}
+	if same > 1 {
+		t.Errorf("Expecting a different key to give a different permutation; Got: %d the same", same)
+	}
+
+	// The same key with a different size gives an unrelated permutation
+	a, b = random.NewPermutation(key, 1000), random.NewPermutation(key, 1001)
+	same = 0
+	for x := uint64(0); x < 1000; x++ {
+		if a.Permute(x) == b.Permute(x) {
+			same++
+		}
+	}
+	if same > 10 {
+		t.Errorf("Expecting a different size to give a different permutation; Got: %d the same", same)
+	}
+}
+
+func TestPermutationEncode(t *testing.T) {
+	t.Parallel()
+	p := random.NewPermutation(random.SecureRandomAES128Key(), 62*62*62*62*62*62)
+	seen := map[string]bool{}
+	for x := uint64(0); x < 5000; x++ {
+		s := p.Encode(x, random.AlphaNumericBytes)
+		if len(s) != 6 || seen[s] {
+			t.Fatalf("Expecting unique 6 character IDs; Got: %q", s)
+		}
+		seen[s] = true
+		if back, ok := p.Decode(s, random.AlphaNumericBytes); !ok || back != x {
+			t.Errorf("Expecting Decode to reverse Encode for %d; Got: %d %t", x, back, ok)
+		}
+	}
+
+	// Every string of the right length and characters is valid when n is a power of the number of characters
+	if _, ok := p.Decode("zzzzzz", random.AlphaNumericBytes); !ok {
+		t.Error("Expecting every 6 character string to decode")
+	}
+	for _, invalid := range []string{"", "abcde", "abcdefg", "abc-ef"} {
+		if _, ok := p.Decode(invalid, random.AlphaNumericBytes); ok {
+			t.Errorf("Expecting %q to not decode", invalid)
+		}
+	}
+
+	// Values at least n do not decode
+	p = random.NewPermutation(random.SecureRandomAES128Key(), 100)
+	if s := p.Encode(99, random.Base64URLBytes); len(s) != 2 {
+		t.Errorf("Expecting 2 characters; Got: %q", s)
+	}
+	if _, ok := p.Decode("__", random.Base64URLBytes); ok {
+		t.Error("Expecting value beyond n to not decode")
+	}
+}
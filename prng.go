@@ -0,0 +1,371 @@
+package random
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// The generators in this file are fast, small, and reproducible, and implement math/rand.Source64,
+// so they can be used with rand.New and all of the Pseudo*Rand functions.
+// Unlike the math/rand source, their state can be saved with MarshalBinary and restored
+// with UnmarshalBinary, and they can be split into non-overlapping streams for parallel work.
+// They are not safe for concurrent use; give each goroutine its own generator.
+// Not cryptographically secure.
+
+// SplitMix64 is the SplitMix64 generator, which has 64 bits of state and a period of 2^64.
+// It is mainly useful for seeding other generators, as any seed, including zero, gives a good stream.
+type SplitMix64 struct {
+	state uint64
+}
+
+// NewSplitMix64 returns a SplitMix64 generator with the given seed.
+func NewSplitMix64(seed uint64) *SplitMix64 {
+	return &SplitMix64{state: seed}
+}
+
+// Uint64 allows implementation of math/rand.Source64
+func (s *SplitMix64) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	return mix64(s.state)
+}
+
+// Int63 allows implementation of math/rand.Source
+func (s *SplitMix64) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// Seed allows implementation of math/rand.Source
+func (s *SplitMix64) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (s *SplitMix64) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint64([]byte(splitMix64Magic), s.state), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (s *SplitMix64) UnmarshalBinary(data []byte) error {
+	state, err := unmarshalState(data, splitMix64Magic, 1)
+	if err != nil {
+		return err
+	}
+	s.state = state[0]
+	return nil
+}
+
+// mix64 is the SplitMix64 output function, which spreads the bits of similar inputs.
+func mix64(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// xoshiro256 is the state shared by the Xoshiro256 generators, which differ only in their output function.
+type xoshiro256 struct {
+	s [4]uint64
+}
+
+// seed fills the state from a SplitMix64 generator, as recommended by the authors,
+// which can never give the invalid all zero state.
+func (x *xoshiro256) seed(seed uint64) {
+	sm := SplitMix64{state: seed}
+	for i := range x.s {
+		x.s[i] = sm.Uint64()
+	}
+}
+
+// ready seeds the state with zero if it is the invalid all zero state of the zero value,
+// which would otherwise only ever give zeros.
+func (x *xoshiro256) ready() {
+	if x.s == ([4]uint64{}) {
+		x.seed(0)
+	}
+}
+
+// step advances the state by one.
+func (x *xoshiro256) step() {
+	t := x.s[1] << 17
+	x.s[2] ^= x.s[0]
+	x.s[3] ^= x.s[1]
+	x.s[1] ^= x.s[2]
+	x.s[0] ^= x.s[3]
+	x.s[2] ^= t
+	x.s[3] = bits.RotateLeft64(x.s[3], 45)
+}
+
+var (
+	xoshiro256Jump     = [4]uint64{0x180ec6d33cfd0aba, 0xd5a61266f0c9392c, 0xa9582618e03fc9aa, 0x39abdc4529b1661c}
+	xoshiro256LongJump = [4]uint64{0x76e15d3efefdcbbf, 0xc5004e441c522fb3, 0x77710069854ee241, 0x39109bb02acbe635}
+)
+
+// jump advances the state by the number of steps encoded in the jump polynomial.
+func (x *xoshiro256) jump(polynomial [4]uint64) {
+	x.ready()
+	var s [4]uint64
+	for _, p := range polynomial {
+		for b := 0; b < 64; b++ {
+			if p&(1<<b) != 0 {
+				s[0] ^= x.s[0]
+				s[1] ^= x.s[1]
+				s[2] ^= x.s[2]
+				s[3] ^= x.s[3]
+			}
+			x.step()
+		}
+	}
+	x.s = s
+}
+
+// marshal returns the state with the magic prefix.
+func (x *xoshiro256) marshal(magic string) []byte {
+	x.ready()
+	b := []byte(magic)
+	for _, v := range x.s {
+		b = binary.BigEndian.AppendUint64(b, v)
+	}
+	return b
+}
+
+// unmarshal sets the state from data with the magic prefix.
+func (x *xoshiro256) unmarshal(data []byte, magic string) error {
+	state, err := unmarshalState(data, magic, 4)
+	if err != nil {
+		return err
+	}
+	if state[0]|state[1]|state[2]|state[3] == 0 {
+		return errors.New("random: invalid " + magic[:len(magic)-1] + " state of all zeros")
+	}
+	copy(x.s[:], state)
+	return nil
+}
+
+// Xoshiro256StarStar is the xoshiro256** generator, which has 256 bits of state and a period of 2^256 - 1.
+// It is a good general purpose generator, that passes all known statistical tests.
+// Use Jump or LongJump to give each worker a non-overlapping stream:
+//
+//	workers := make([]random.Xoshiro256StarStar, n)
+//	x := random.NewXoshiro256StarStar(seed)
+//	for i := range workers {
+//		workers[i] = *x // each copy has 2^128 values before reaching the next one
+//		x.Jump()
+//	}
+//
+// The zero value is ready to use, and gives the same values as NewXoshiro256StarStar(0).
+type Xoshiro256StarStar struct {
+	xoshiro256
+}
+
+// NewXoshiro256StarStar returns a Xoshiro256StarStar generator with its state seeded by SplitMix64.
+func NewXoshiro256StarStar(seed uint64) *Xoshiro256StarStar {
+	x := &Xoshiro256StarStar{}
+	x.seed(seed)
+	return x
+}
+
+// Uint64 allows implementation of math/rand.Source64
+func (x *Xoshiro256StarStar) Uint64() uint64 {
+	x.ready()
+	result := bits.RotateLeft64(x.s[1]*5, 7) * 9
+	x.step()
+	return result
+}
+
+// Int63 allows implementation of math/rand.Source
+func (x *Xoshiro256StarStar) Int63() int64 {
+	return int64(x.Uint64() >> 1)
+}
+
+// Seed allows implementation of math/rand.Source
+func (x *Xoshiro256StarStar) Seed(seed int64) {
+	x.seed(uint64(seed))
+}
+
+// Jump advances the generator by 2^128 values, the same as 2^128 calls to Uint64.
+// It can be used to create 2^128 non-overlapping streams for parallel work.
+func (x *Xoshiro256StarStar) Jump() {
+	x.jump(xoshiro256Jump)
+}
+
+// LongJump advances the generator by 2^192 values, the same as 2^192 calls to Uint64.
+// It can be used to create 2^64 starting points, from each of which Jump can create 2^64 more streams.
+func (x *Xoshiro256StarStar) LongJump() {
+	x.jump(xoshiro256LongJump)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (x *Xoshiro256StarStar) MarshalBinary() ([]byte, error) {
+	return x.marshal(xoshiro256StarStarMagic), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (x *Xoshiro256StarStar) UnmarshalBinary(data []byte) error {
+	return x.unmarshal(data, xoshiro256StarStarMagic)
+}
+
+// Xoshiro256Plus is the xoshiro256+ generator, which is slightly faster than Xoshiro256StarStar,
+// but whose lowest 3 bits fail linearity tests. It is best used for floating point numbers,
+// which only use the upper bits.
+// See Xoshiro256StarStar for how to give each worker a non-overlapping stream.
+// The zero value is ready to use, and gives the same values as NewXoshiro256Plus(0).
+type Xoshiro256Plus struct {
+	xoshiro256
+}
+
+// NewXoshiro256Plus returns a Xoshiro256Plus generator with its state seeded by SplitMix64.
+func NewXoshiro256Plus(seed uint64) *Xoshiro256Plus {
+	x := &Xoshiro256Plus{}
+	x.seed(seed)
+	return x
+}
+
+// Uint64 allows implementation of math/rand.Source64
+func (x *Xoshiro256Plus) Uint64() uint64 {
+	x.ready()
+	result := x.s[0] + x.s[3]
+	x.step()
+	return result
+}
+
+// Int63 allows implementation of math/rand.Source
+func (x *Xoshiro256Plus) Int63() int64 {
+	return int64(x.Uint64() >> 1)
+}
+
+// Seed allows implementation of math/rand.Source
+func (x *Xoshiro256Plus) Seed(seed int64) {
+	x.seed(uint64(seed))
+}
+
+// Jump advances the generator by 2^128 values, the same as 2^128 calls to Uint64.
+// It can be used to create 2^128 non-overlapping streams for parallel work.
+func (x *Xoshiro256Plus) Jump() {
+	x.jump(xoshiro256Jump)
+}
+
+// LongJump advances the generator by 2^192 values, the same as 2^192 calls to Uint64.
+// It can be used to create 2^64 starting points, from each of which Jump can create 2^64 more streams.
+func (x *Xoshiro256Plus) LongJump() {
+	x.jump(xoshiro256LongJump)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (x *Xoshiro256Plus) MarshalBinary() ([]byte, error) {
+	return x.marshal(xoshiro256PlusMagic), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (x *Xoshiro256Plus) UnmarshalBinary(data []byte) error {
+	return x.unmarshal(data, xoshiro256PlusMagic)
+}
+
+// PCG64 is the PCG XSL RR 128/64 generator (pcg64), which has 128 bits of state and a period of 2^128.
+// Every stream has its own sequence, so giving each worker a different stream
+// gives them independent sequences, without needing to coordinate seeds.
+// The zero value is ready to use, and gives the same values as NewPCG64(0, 0).
+type PCG64 struct {
+	hi, lo       uint64 // state
+	incHi, incLo uint64 // increment, which is always odd, and selects the stream
+}
+
+// pcg64MultiplierHi and pcg64MultiplierLo are the halves of the 128 bit multiplier of the linear congruential generator.
+const (
+	pcg64MultiplierHi = 2549297995355413924
+	pcg64MultiplierLo = 4865540595714422341
+)
+
+// NewPCG64 returns a PCG64 generator with the given seed, on the given stream.
+func NewPCG64(seed, stream uint64) *PCG64 {
+	p := &PCG64{incHi: stream >> 63, incLo: stream<<1 | 1}
+	p.Seed(int64(seed))
+	return p
+}
+
+// ready seeds the generator with zero on stream zero if its increment is even, as it is for
+// the zero value, which would otherwise have a much shorter period.
+func (p *PCG64) ready() {
+	if p.incLo&1 == 0 {
+		p.incHi, p.incLo = 0, 1
+		p.Seed(0)
+	}
+}
+
+// step advances the state by one.
+func (p *PCG64) step() {
+	hi, lo := bits.Mul64(p.lo, pcg64MultiplierLo)
+	hi += p.hi*pcg64MultiplierLo + p.lo*pcg64MultiplierHi
+	var carry uint64
+	p.lo, carry = bits.Add64(lo, p.incLo, 0)
+	p.hi, _ = bits.Add64(hi, p.incHi, carry)
+}
+
+// Uint64 allows implementation of math/rand.Source64
+func (p *PCG64) Uint64() uint64 {
+	p.ready()
+	p.step()
+	return bits.RotateLeft64(p.hi^p.lo, -int(p.hi>>58))
+}
+
+// Int63 allows implementation of math/rand.Source
+func (p *PCG64) Int63() int64 {
+	return int64(p.Uint64() >> 1)
+}
+
+// Seed allows implementation of math/rand.Source.
+// The generator stays on the same stream, or uses stream zero if it is the zero value.
+func (p *PCG64) Seed(seed int64) {
+	p.ready()
+	p.hi, p.lo = 0, 0
+	p.step()
+	var carry uint64
+	p.lo, carry = bits.Add64(p.lo, uint64(seed), 0)
+	p.hi += carry
+	p.step()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (p *PCG64) MarshalBinary() ([]byte, error) {
+	p.ready()
+	b := []byte(pcg64Magic)
+	for _, v := range [4]uint64{p.hi, p.lo, p.incHi, p.incLo} {
+		b = binary.BigEndian.AppendUint64(b, v)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (p *PCG64) UnmarshalBinary(data []byte) error {
+	state, err := unmarshalState(data, pcg64Magic, 4)
+	if err != nil {
+		return err
+	}
+	if state[3]&1 == 0 {
+		return errors.New("random: invalid pcg64 state with even increment")
+	}
+	p.hi, p.lo, p.incHi, p.incLo = state[0], state[1], state[2], state[3]
+	return nil
+}
+
+// Magic prefixes of the marshalled state of each generator, so that the state of one is never restored into another.
+const (
+	splitMix64Magic         = "splitmix64:"
+	xoshiro256StarStarMagic = "xoshiro256**:"
+	xoshiro256PlusMagic     = "xoshiro256+:"
+	pcg64Magic              = "pcg64:"
+	recordingSourceMagic    = "recording:"
+)
+
+// unmarshalState checks the magic prefix and length of data, and returns the words of state that follow the prefix.
+func unmarshalState(data []byte, magic string, words int) ([]uint64, error) {
+	if !bytes.HasPrefix(data, []byte(magic)) || len(data) != len(magic)+8*words {
+		return nil, errors.New("random: invalid " + magic[:len(magic)-1] + " state")
+	}
+	data = data[len(magic):]
+	state := make([]uint64, words)
+	for i := range state {
+		state[i] = binary.BigEndian.Uint64(data[8*i:])
+	}
+	return state, nil
+}
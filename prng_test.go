@@ -0,0 +1,136 @@
+package random_test
+
+import (
+	"encoding"
+	"math/rand"
+	"testing"
+
+	"github.com/veqryn/go-random"
+)
+
+// Reference values are from the authors' C implementations.
+func TestPRNGReferenceValues(t *testing.T) {
+	t.Parallel()
+	jumped := random.NewXoshiro256StarStar(1234567)
+	jumped.Jump()
+	longJumped := random.NewXoshiro256StarStar(1234567)
+	longJumped.LongJump()
+
+	tests := []struct {
+		name     string
+		source   rand.Source64
+		expected []uint64
+	}{
+		{"splitmix64", random.NewSplitMix64(1234567), []uint64{6457827717110365317, 3203168211198807973, 9817491932198370423}},
+		{"xoshiro256**", random.NewXoshiro256StarStar(1234567), []uint64{3504822795582309479, 1819558768956484042, 1250851346055027673}},
+		{"xoshiro256+", random.NewXoshiro256Plus(1234567), []uint64{11051208245235447748, 13323646940265848391, 11259839391761139050}},
+		{"xoshiro256** jump", jumped, []uint64{15294322188766636806, 10827428027782516218, 14138413806026728362}},
+		{"xoshiro256** long jump", longJumped, []uint64{3406981024813772628, 11539772556808048623, 5989444222632535258}},
+		{"pcg64", random.NewPCG64(42, 54), []uint64{9705778491962043240, 1370407407632858425, 11774395822783136600}},
+		{"pcg64 stream", random.NewPCG64(42, 55), []uint64{6815944901667806851, 12706679542934099394, 3021032444823341312}},
+	}
+	for _, tc := range tests {
+		for i, expected := range tc.expected {
+			if got := tc.source.Uint64(); got != expected {
+				t.Errorf("Expecting %s value %d to be %d; Got: %d", tc.name, i, expected, got)
+			}
+		}
+	}
+}
+
+type marshallingSource interface {
+	rand.Source64
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+func TestPRNGMarshalBinary(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		source, restored marshallingSource
+	}{
+		{random.NewSplitMix64(1), &random.SplitMix64{}},
+		{random.NewXoshiro256StarStar(1), &random.Xoshiro256StarStar{}},
+		{random.NewXoshiro256Plus(1), &random.Xoshiro256Plus{}},
+		{random.NewPCG64(1, 2), &random.PCG64{}},
+	}
+	for _, tc := range tests {
+		tc.source.Uint64()
+		state, err := tc.source.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = tc.restored.UnmarshalBinary(state); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			if a, b := tc.source.Uint64(), tc.restored.Uint64(); a != b {
+				t.Fatalf("Expecting restored %T to continue the same stream; Got: %d and %d", tc.source, a, b)
+			}
+		}
+		if err = tc.restored.UnmarshalBinary(state[:len(state)-1]); err == nil {
+			t.Errorf("Expecting error for truncated %T state", tc.source)
+		}
+	}
+
+	// The state of one generator can not be restored into another
+	state, _ := random.NewXoshiro256Plus(1).MarshalBinary()
+	if err := (&random.Xoshiro256StarStar{}).UnmarshalBinary(state); err == nil {
+		t.Error("Expecting error when restoring xoshiro256+ state into xoshiro256**")
+	}
+}
+
+func TestPRNGSeed(t *testing.T) {
+	t.Parallel()
+	for _, source := range []rand.Source64{random.NewSplitMix64(1), random.NewXoshiro256StarStar(1), random.NewXoshiro256Plus(1), random.NewPCG64(1, 7)} {
+		r := rand.New(source)
+		r.Seed(99)
+		first := r.Int63()
+		r.Seed(99)
+		if second := r.Int63(); first != second || first < 0 {
+			t.Errorf("Expecting %T to restart its sequence when seeded; Got: %d and %d", source, first, second)
+		}
+		if n := random.PseudoRandomInt63Rand(r, 10, 20); n < 10 || n >= 20 {
+			t.Errorf("Expecting %T to work with the Pseudo*Rand functions; Got: %d", source, n)
+		}
+	}
+}
+
+func TestPRNGZeroValue(t *testing.T) {
+	t.Parallel()
+	// The zero value is the same as seeding with zero, rather than the invalid all zero state
+	tests := []struct {
+		zero, seeded rand.Source64
+	}{
+		{&random.SplitMix64{}, random.NewSplitMix64(0)},
+		{&random.Xoshiro256StarStar{}, random.NewXoshiro256StarStar(0)},
+		{&random.Xoshiro256Plus{}, random.NewXoshiro256Plus(0)},
+		{&random.PCG64{}, random.NewPCG64(0, 0)},
+	}
+	for _, tc := range tests {
+		for i := 0; i < 10; i++ {
+			if a, b := tc.zero.Uint64(), tc.seeded.Uint64(); a != b || a == 0 {
+				t.Errorf("Expecting the zero value of %T to give the values of seed zero; Got: %d and %d", tc.zero, a, b)
+			}
+		}
+	}
+
+	// Jumping, marshalling and seeding the zero value also start from seed zero
+	var jumped random.Xoshiro256StarStar
+	jumped.Jump()
+	expected := random.NewXoshiro256StarStar(0)
+	expected.Jump()
+	if a, b := jumped.Uint64(), expected.Uint64(); a != b {
+		t.Errorf("Expecting the zero value to jump from seed zero; Got: %d and %d", a, b)
+	}
+	var plus random.Xoshiro256Plus
+	state, _ := plus.MarshalBinary()
+	if err := (&random.Xoshiro256Plus{}).UnmarshalBinary(state); err != nil {
+		t.Errorf("Expecting the marshalled zero value to be valid; Got: %v", err)
+	}
+	var pcg random.PCG64
+	pcg.Seed(5)
+	if a, b := pcg.Uint64(), random.NewPCG64(5, 0).Uint64(); a != b {
+		t.Errorf("Expecting the seeded zero value to be on stream zero; Got: %d and %d", a, b)
+	}
+}
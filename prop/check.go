@@ -0,0 +1,88 @@
+package prop
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/veqryn/go-random/randtest"
+)
+
+// Config controls how Check runs a property.
+type Config struct {
+	// Runs is how many values to test. If zero, the default is 100.
+	Runs int
+
+	// Seed is the seed of the first run, each run after that adds one to it.
+	// If zero, the seed is randtest.Seed(t), so the -random.seed flag or RANDOM_SEED
+	// environment variable replays a failure, as for the rest of the test.
+	Seed int64
+
+	// MaxShrinks is how many times a failing value can be shrunk. If zero, the default is 1000.
+	MaxShrinks int
+}
+
+// Check tests that the property holds for values from the generator, using the default Config.
+// See CheckWith.
+func Check[T any](t testing.TB, gen Gen[T], property func(T) bool) bool {
+	t.Helper()
+	return CheckWith(t, Config{}, gen, property)
+}
+
+// CheckWith tests that the property holds for values from the generator.
+// A property fails if it returns false or panics. The first failing value is shrunk
+// to the smallest value that still fails, which is reported with t.Errorf along with
+// the seed that reproduces it. It returns true if the property held for every value.
+func CheckWith[T any](t testing.TB, config Config, gen Gen[T], property func(T) bool) bool {
+	t.Helper()
+	if config.Runs == 0 {
+		config.Runs = 100
+	}
+	if config.MaxShrinks == 0 {
+		config.MaxShrinks = 1000
+	}
+	if config.Seed == 0 {
+		config.Seed = randtest.Seed(t)
+	}
+
+	for run := 0; run < config.Runs; run++ {
+		seed := config.Seed + int64(run)
+		failing := gen.run(rand.New(rand.NewSource(seed)))
+		failed, reason := fails(property, failing.value)
+		if !failed {
+			continue
+		}
+		original := failing.value
+
+		// Shrink by moving to the first candidate that still fails, until none do
+		shrinks := 0
+	shrinking:
+		for shrinks < config.MaxShrinks {
+			for _, candidate := range failing.shrinks() {
+				if candidateFailed, candidateReason := fails(property, candidate.value); candidateFailed {
+					failing, reason = candidate, candidateReason
+					shrinks++
+					continue shrinking
+				}
+			}
+			break
+		}
+
+		t.Errorf("prop: property failed after %d runs, with seed %d (replay with Config.Seed=%d)\n"+
+			"shrunk %d times to: %#v\n"+
+			"original: %#v%s", run+1, seed, seed, shrinks, failing.value, original, reason)
+		return false
+	}
+	return true
+}
+
+// fails returns true if the property returns false or panics for the value,
+// along with a description of the panic, if any.
+func fails[T any](property func(T) bool, value T) (failed bool, reason string) {
+	defer func() {
+		if r := recover(); r != nil {
+			failed, reason = true, fmt.Sprintf("\npanic: %v", r)
+		}
+	}()
+	return !property(value), ""
+}
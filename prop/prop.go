@@ -0,0 +1,337 @@
+// Package prop is a property-based testing library, similar to testing/quick, with
+// composable generators, custom alphabets, and automatic shrinking of failing inputs.
+//
+// Generators are built from the Pseudo*Rand functions of the random package, and
+// every generated value carries its own shrink candidates, so values created with
+// Map, Filter, SliceOf and the other combinators shrink without any extra code.
+//
+//	func TestReverse(t *testing.T) {
+//		gen := prop.SliceOf(prop.Int(-100, 100), 0, 20)
+//		prop.Check(t, gen, func(s []int) bool {
+//			return slices.Equal(s, reverse(reverse(s)))
+//		})
+//	}
+package prop
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/veqryn/go-random"
+)
+
+// Gen generates random values of type T, along with ways to shrink them.
+type Gen[T any] struct {
+	run func(rand *rand.Rand) tree[T]
+}
+
+// tree is a generated value and its shrink candidates, from most to least shrunk.
+// Candidates are computed lazily, as they are only needed when a property fails.
+type tree[T any] struct {
+	value   T
+	shrinks func() []tree[T]
+}
+
+// Sample returns a value from the generator.
+func (g Gen[T]) Sample(rand *rand.Rand) T {
+	return g.run(rand).value
+}
+
+// unfold returns a tree whose shrink candidates are found by repeatedly calling shrink.
+func unfold[T any](value T, shrink func(T) []T) tree[T] {
+	return tree[T]{value: value, shrinks: func() []tree[T] {
+		candidates := shrink(value)
+		trees := make([]tree[T], len(candidates))
+		for i, c := range candidates {
+			trees[i] = unfold(c, shrink)
+		}
+		return trees
+	}}
+}
+
+// mapTree applies f to every value in the tree.
+func mapTree[T, U any](t tree[T], f func(T) U) tree[U] {
+	return tree[U]{value: f(t.value), shrinks: func() []tree[U] {
+		children := t.shrinks()
+		mapped := make([]tree[U], len(children))
+		for i, c := range children {
+			mapped[i] = mapTree(c, f)
+		}
+		return mapped
+	}}
+}
+
+// filterTree removes shrink candidates that do not satisfy keep.
+// A removed candidate is replaced by its own candidates that satisfy keep, so that
+// shrinking is not stopped by a single unsatisfying value (ie: an odd number when
+// only even numbers are kept).
+func filterTree[T any](t tree[T], keep func(T) bool) tree[T] {
+	return tree[T]{value: t.value, shrinks: func() []tree[T] {
+		var kept []tree[T]
+		for _, c := range t.shrinks() {
+			if keep(c.value) {
+				kept = append(kept, filterTree(c, keep))
+				continue
+			}
+			for _, grandchild := range c.shrinks() {
+				if keep(grandchild.value) {
+					kept = append(kept, filterTree(grandchild, keep))
+				}
+			}
+		}
+		return kept
+	}}
+}
+
+// towards returns values between target and n, starting at target and halving
+// the distance each time, for shrinking n towards target.
+func towards(target, n int64) []int64 {
+	var candidates []int64
+	for diff := n - target; diff != 0; diff /= 2 {
+		candidates = append(candidates, n-diff)
+	}
+	return candidates
+}
+
+// Int generates integers between [minInclusive, maxExclusive), which shrink towards
+// zero, or towards minInclusive if zero is not in range.
+// If max - min <= 0, this will panic.
+func Int(minInclusive, maxExclusive int) Gen[int] {
+	if maxExclusive <= minInclusive {
+		panic("prop: maxExclusive must be greater than minInclusive")
+	}
+	origin := int64(0)
+	if origin < int64(minInclusive) || origin >= int64(maxExclusive) {
+		origin = int64(minInclusive)
+	}
+	// The span is computed as a uint64, as it can be more than math.MaxInt64, such as for Int(math.MinInt, math.MaxInt)
+	span := uint64(maxExclusive) - uint64(minInclusive)
+	return Gen[int]{run: func(rand *rand.Rand) tree[int] {
+		n := int64(uint64(minInclusive) + uint64n(rand, span))
+		return mapTree(unfold(n, func(n int64) []int64 { return towards(origin, n) }), func(n int64) int { return int(n) })
+	}}
+}
+
+// uint64n returns a random number between [0, n). If n is zero, this will panic.
+func uint64n(rand *rand.Rand, n uint64) uint64 {
+	if n <= math.MaxInt64 {
+		return uint64(rand.Int63n(int64(n)))
+	}
+	// n is more than half the range of uint64, so fewer than half of the values are rejected
+	for {
+		if v := rand.Uint64(); v < n {
+			return v
+		}
+	}
+}
+
+// Bool generates booleans, which shrink towards false.
+func Bool() Gen[bool] {
+	return Gen[bool]{run: func(rand *rand.Rand) tree[bool] {
+		return unfold(rand.Intn(2) == 1, func(b bool) []bool {
+			if b {
+				return []bool{false}
+			}
+			return nil
+		})
+	}}
+}
+
+// Const always generates the same value.
+func Const[T any](value T) Gen[T] {
+	return Gen[T]{run: func(*rand.Rand) tree[T] {
+		return tree[T]{value: value, shrinks: func() []tree[T] { return nil }}
+	}}
+}
+
+// String generates strings made from the available character bytes, such as
+// random.AlphaNumericBytes, with a length between [minLen, maxLen].
+// Strings shrink by removing characters, then by replacing characters with
+// ones earlier in availableCharBytes.
+// If the available character bytes slice is empty, or the lengths are invalid, this will panic.
+func String(availableCharBytes []byte, minLen, maxLen int) Gen[string] {
+	if len(availableCharBytes) == 0 {
+		panic("prop: availableCharBytes must not be empty")
+	}
+	checkLengths(minLen, maxLen)
+	indices := map[byte]int{}
+	for i, c := range availableCharBytes {
+		if _, ok := indices[c]; !ok {
+			indices[c] = i
+		}
+	}
+	return Gen[string]{run: func(rand *rand.Rand) tree[string] {
+		length := int(random.PseudoRandomInt63Rand(rand, int64(minLen), int64(maxLen)+1))
+		s := random.PseudoRandomStringBytesRand(rand, length, availableCharBytes)
+		return unfold(s, func(s string) []string {
+			var candidates []string
+			for _, chunk := range removeChunks(len(s), minLen) {
+				candidates = append(candidates, s[:chunk[0]]+s[chunk[1]:])
+			}
+			for i := 0; i < len(s); i++ {
+				for _, idx := range towards(0, int64(indices[s[i]])) {
+					candidates = append(candidates, s[:i]+string(availableCharBytes[idx])+s[i+1:])
+				}
+			}
+			return candidates
+		})
+	}}
+}
+
+// removeChunks returns the [start, end) ranges that can be removed from a sequence
+// of the given length without going below minLen, from largest to smallest.
+func removeChunks(length, minLen int) [][2]int {
+	var chunks [][2]int
+	for size := length - minLen; size > 0; size /= 2 {
+		for start := 0; start+size <= length; start += size {
+			chunks = append(chunks, [2]int{start, start + size})
+		}
+	}
+	return chunks
+}
+
+// checkLengths panics if the lengths are invalid.
+func checkLengths(minLen, maxLen int) {
+	if minLen < 0 || maxLen < minLen {
+		panic("prop: minLen must not be negative or greater than maxLen")
+	}
+}
+
+// SliceOf generates slices with a length between [minLen, maxLen], with elements from g.
+// Slices shrink by removing elements, then by shrinking each element.
+// If the lengths are invalid, this will panic.
+func SliceOf[T any](g Gen[T], minLen, maxLen int) Gen[[]T] {
+	checkLengths(minLen, maxLen)
+	return Gen[[]T]{run: func(rand *rand.Rand) tree[[]T] {
+		length := int(random.PseudoRandomInt63Rand(rand, int64(minLen), int64(maxLen)+1))
+		elements := make([]tree[T], length)
+		for i := range elements {
+			elements[i] = g.run(rand)
+		}
+		return sliceTree(elements, minLen)
+	}}
+}
+
+// sliceTree returns the tree for a slice made of the element trees.
+func sliceTree[T any](elements []tree[T], minLen int) tree[[]T] {
+	values := make([]T, len(elements))
+	for i, e := range elements {
+		values[i] = e.value
+	}
+	return tree[[]T]{value: values, shrinks: func() []tree[[]T] {
+		var candidates []tree[[]T]
+		for _, chunk := range removeChunks(len(elements), minLen) {
+			shorter := append(append([]tree[T]{}, elements[:chunk[0]]...), elements[chunk[1]:]...)
+			candidates = append(candidates, sliceTree(shorter, minLen))
+		}
+		for i, e := range elements {
+			for _, shrunk := range e.shrinks() {
+				replaced := append([]tree[T]{}, elements...)
+				replaced[i] = shrunk
+				candidates = append(candidates, sliceTree(replaced, minLen))
+			}
+		}
+		return candidates
+	}}
+}
+
+// MapOf generates maps with up to maxLen entries, with keys and values from the generators.
+// Duplicate keys are overwritten, so maps may have fewer than minLen entries if the keys
+// generator has few possible values.
+// Maps shrink by removing entries, then by shrinking keys and values.
+// If the lengths are invalid, this will panic.
+func MapOf[K comparable, V any](keys Gen[K], values Gen[V], minLen, maxLen int) Gen[map[K]V] {
+	type entry struct {
+		key   K
+		value V
+	}
+	entries := Gen[entry]{run: func(rand *rand.Rand) tree[entry] {
+		k, v := keys.run(rand), values.run(rand)
+		return pairTree(k, v, func(k K, v V) entry { return entry{key: k, value: v} })
+	}}
+	return Map(SliceOf(entries, minLen, maxLen), func(entries []entry) map[K]V {
+		m := make(map[K]V, len(entries))
+		for _, e := range entries {
+			m[e.key] = e.value
+		}
+		return m
+	})
+}
+
+// pairTree combines two trees, shrinking the first and then the second.
+func pairTree[A, B, T any](a tree[A], b tree[B], combine func(A, B) T) tree[T] {
+	return tree[T]{value: combine(a.value, b.value), shrinks: func() []tree[T] {
+		var candidates []tree[T]
+		for _, shrunk := range a.shrinks() {
+			candidates = append(candidates, pairTree(shrunk, b, combine))
+		}
+		for _, shrunk := range b.shrinks() {
+			candidates = append(candidates, pairTree(a, shrunk, combine))
+		}
+		return candidates
+	}}
+}
+
+// Map generates values from g transformed by f. The results shrink as the values from g do.
+func Map[T, U any](g Gen[T], f func(T) U) Gen[U] {
+	return Gen[U]{run: func(rand *rand.Rand) tree[U] {
+		return mapTree(g.run(rand), f)
+	}}
+}
+
+// filterMaxAttempts is how many values Filter generates looking for one that satisfies the predicate.
+const filterMaxAttempts = 1000
+
+// Filter generates values from g that satisfy keep, and only shrinks to values that satisfy keep.
+// If no value is found after many attempts, this will panic.
+func Filter[T any](g Gen[T], keep func(T) bool) Gen[T] {
+	return Gen[T]{run: func(rand *rand.Rand) tree[T] {
+		for attempted := 0; attempted < filterMaxAttempts; attempted++ {
+			if t := g.run(rand); keep(t.value) {
+				return filterTree(t, keep)
+			}
+		}
+		panic("prop: Filter could not find a value that satisfies the predicate")
+	}}
+}
+
+// OneOf generates values from one of the generators, each equally likely.
+// If no generators are given, this will panic.
+func OneOf[T any](gens ...Gen[T]) Gen[T] {
+	weighted := make([]Weight[T], len(gens))
+	for i, g := range gens {
+		weighted[i] = Weight[T]{Weight: 1, Gen: g}
+	}
+	return Weighted(weighted...)
+}
+
+// Weight is a generator and how likely it is to be chosen by Weighted.
+type Weight[T any] struct {
+	Weight int
+	Gen    Gen[T]
+}
+
+// Weighted generates values from one of the generators, chosen in proportion to its weight.
+// If no generators are given, or any weight is not positive, this will panic.
+func Weighted[T any](choices ...Weight[T]) Gen[T] {
+	if len(choices) == 0 {
+		panic("prop: at least one generator is required")
+	}
+	total := int64(0)
+	for _, c := range choices {
+		if c.Weight <= 0 {
+			panic("prop: weights must be positive")
+		}
+		total += int64(c.Weight)
+	}
+	return Gen[T]{run: func(rand *rand.Rand) tree[T] {
+		n := random.PseudoRandomInt63Rand(rand, 0, total)
+		for _, c := range choices {
+			if n < int64(c.Weight) {
+				return c.Gen.run(rand)
+			}
+			n -= int64(c.Weight)
+		}
+		panic("prop: no generator chosen") // Impossible
+	}}
+}
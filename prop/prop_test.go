@@ -0,0 +1,169 @@
+package prop_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/prop"
+	"github.com/veqryn/go-random/randtest"
+)
+
+// recorder captures failures reported by Check, instead of failing the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+// shrunk returns the shrunk value line of the first failure.
+func (r *recorder) shrunk() string {
+	if len(r.failures) == 0 {
+		return ""
+	}
+	for _, line := range strings.Split(r.failures[0], "\n") {
+		if strings.HasPrefix(line, "shrunk") {
+			return line[strings.Index(line, ": ")+2:]
+		}
+	}
+	return ""
+}
+
+func TestCheckPasses(t *testing.T) {
+	t.Parallel()
+	gen := prop.SliceOf(prop.Int(-100, 100), 0, 20)
+	prop.Check(t, gen, func(s []int) bool {
+		for _, n := range s {
+			if n < -100 || n >= 100 {
+				return false
+			}
+		}
+		return len(s) <= 20
+	})
+}
+
+func TestCheckShrinksInt(t *testing.T) {
+	t.Parallel()
+	r := &recorder{TB: t}
+	if prop.Check(r, prop.Int(0, 1000), func(n int) bool { return n < 50 }) {
+		t.Fatal("Expecting property to fail")
+	}
+	if r.shrunk() != "50" {
+		t.Errorf("Expecting to shrink to 50; Got: %s", r.failures)
+	}
+}
+
+func TestIntFullRange(t *testing.T) {
+	t.Parallel()
+	// The span of the full range does not fit in an int64
+	gen := prop.Int(math.MinInt, math.MaxInt)
+	source := randtest.Rand(t)
+	var negative, positive int
+	for i := 0; i < 1000; i++ {
+		switch n := gen.Sample(source); {
+		case n == math.MaxInt:
+			t.Fatalf("Expecting max to be exclusive; Got: %d", n)
+		case n < 0:
+			negative++
+		case n > 0:
+			positive++
+		}
+	}
+	if negative < 400 || positive < 400 {
+		t.Errorf("Expecting values across the whole range; Got: %d negative and %d positive", negative, positive)
+	}
+
+	r := &recorder{TB: t}
+	prop.Check(r, gen, func(n int) bool { return n < 1000 && n > -1000 })
+	if shrunk := r.shrunk(); shrunk != "1000" && shrunk != "-1000" {
+		t.Errorf("Expecting to shrink to 1000 or -1000; Got: %s", r.failures)
+	}
+}
+
+func TestCheckShrinksSlice(t *testing.T) {
+	t.Parallel()
+	r := &recorder{TB: t}
+	prop.Check(r, prop.SliceOf(prop.Int(-10, 10), 0, 30), func(s []int) bool { return len(s) < 3 })
+	if r.shrunk() != "[]int{0, 0, 0}" {
+		t.Errorf("Expecting to shrink to 3 zeros; Got: %s", r.failures)
+	}
+}
+
+func TestCheckShrinksString(t *testing.T) {
+	t.Parallel()
+	r := &recorder{TB: t}
+	prop.Check(r, prop.String([]byte("abcz"), 0, 30), func(s string) bool { return !strings.Contains(s, "cz") })
+	if r.shrunk() != `"cz"` {
+		t.Errorf("Expecting to shrink to \"cz\"; Got: %s", r.failures)
+	}
+}
+
+func TestCheckShrinksMapAndFilter(t *testing.T) {
+	t.Parallel()
+	r := &recorder{TB: t}
+	even := prop.Filter(prop.Int(0, 1000), func(n int) bool { return n%2 == 0 })
+	doubled := prop.Map(even, func(n int) string { return fmt.Sprint(n * 2) })
+	prop.Check(r, doubled, func(s string) bool { return len(s) < 3 })
+	if r.shrunk() != `"100"` {
+		t.Errorf("Expecting to shrink to \"100\"; Got: %s", r.failures)
+	}
+}
+
+func TestCheckPanicFails(t *testing.T) {
+	t.Parallel()
+	r := &recorder{TB: t}
+	prop.Check(r, prop.Bool(), func(b bool) bool {
+		if b {
+			panic("boom")
+		}
+		return true
+	})
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "panic: boom") || r.shrunk() != "true" {
+		t.Errorf("Expecting panic to be reported; Got: %s", r.failures)
+	}
+}
+
+func TestCheckReplaySeed(t *testing.T) {
+	t.Parallel()
+	gen := prop.MapOf(prop.String(random.HexBytes, 1, 4), prop.OneOf(prop.Int(0, 10), prop.Const(-1)), 0, 10)
+	seed := int64(12345)
+	a := gen.Sample(rand.New(rand.NewSource(seed)))
+	b := gen.Sample(rand.New(rand.NewSource(seed)))
+	if fmt.Sprint(a) != fmt.Sprint(b) {
+		t.Errorf("Expecting the same seed to give the same value; Got: %v and %v", a, b)
+	}
+
+	r := &recorder{TB: t}
+	prop.CheckWith(r, prop.Config{Seed: seed, Runs: 1}, prop.Int(0, 10), func(int) bool { return false })
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "seed 12345") {
+		t.Errorf("Expecting failure to report the seed; Got: %s", r.failures)
+	}
+
+	// Without a seed, the test's randtest seed is used, so -random.seed replays it
+	r = &recorder{TB: t}
+	prop.CheckWith(r, prop.Config{Runs: 1}, prop.Int(0, 10), func(int) bool { return false })
+	if expected := fmt.Sprintf("seed %d ", randtest.Seed(t)); len(r.failures) != 1 || !strings.Contains(r.failures[0], expected) {
+		t.Errorf("Expecting failure to report the randtest %s; Got: %s", expected, r.failures)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	t.Parallel()
+	gen := prop.Weighted(prop.Weight[string]{Weight: 9, Gen: prop.Const("a")}, prop.Weight[string]{Weight: 1, Gen: prop.Const("b")})
+	source := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		counts[gen.Sample(source)]++
+	}
+	if counts["a"] < 8500 || counts["a"] > 9500 {
+		t.Errorf("Expecting roughly 9000 a's; Got: %v", counts)
+	}
+}
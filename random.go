@@ -10,6 +10,19 @@ const (
 	AlphaNumeric          = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
 	Base64URL             = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
 	Base64Std             = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+	// Crockford32 is Douglas Crockford's base32 alphabet, which excludes I, L, O and U.
+	Crockford32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// AlphabetNoVowels and AlphaNumericNoVowels exclude vowels (including Y),
+	// making it unlikely that a random string accidentally spells a word.
+	AlphabetNoVowels     = "BCDFGHJKLMNPQRSTVWXZ"
+	AlphaNumericNoVowels = "BCDFGHJKLMNPQRSTVWXZ0123456789"
+
+	// Unambiguous and UnambiguousNoVowels are upper-case only and exclude the
+	// easily confused characters 0, O, 1, I and L, for codes read or typed by people.
+	Unambiguous         = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+	UnambiguousNoVowels = "BCDFGHJKMNPQRSTVWXZ23456789"
 )
 
 var (
@@ -19,4 +32,9 @@ var (
 	AlphaNumericBytes          = []byte(AlphaNumeric)
 	Base64URLBytes             = []byte(Base64URL)
 	Base64StdBytes             = []byte(Base64Std)
+	Crockford32Bytes           = []byte(Crockford32)
+	AlphabetNoVowelsBytes      = []byte(AlphabetNoVowels)
+	AlphaNumericNoVowelsBytes  = []byte(AlphaNumericNoVowels)
+	UnambiguousBytes           = []byte(Unambiguous)
+	UnambiguousNoVowelsBytes   = []byte(UnambiguousNoVowels)
 )
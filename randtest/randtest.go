@@ -0,0 +1,90 @@
+// Package randtest provides reproducible *math/rand.Rand sources for tests.
+//
+// Every test gets its own stream, derived from a single root seed and the test's name,
+// so parallel tests and subtests are independent of each other and of the order they run in.
+// The root seed is chosen with crypto/rand, unless overridden with the -random.seed flag
+// or the RANDOM_SEED environment variable. When a test fails, the root seed is logged,
+// so the failure can be replayed:
+//
+//	go test -run TestSomething -random.seed=1234567890
+package randtest
+
+import (
+	"encoding/binary"
+	"flag"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/veqryn/go-random"
+)
+
+// SeedEnv is the environment variable that overrides the root seed, if the -random.seed flag is not set.
+const SeedEnv = "RANDOM_SEED"
+
+var seedFlag = flag.String("random.seed", "", "root seed for randtest, to replay a failing test (default is random)")
+
+var (
+	rootSeedOnce sync.Once
+	rootSeed     int64
+)
+
+// RootSeed returns the root seed for this test binary. It is chosen once, from the
+// -random.seed flag, the SeedEnv environment variable, or crypto/rand, in that order.
+// If the flag or environment variable is not a valid int64, this will panic.
+func RootSeed() int64 {
+	rootSeedOnce.Do(func() {
+		override := *seedFlag
+		if override == "" {
+			override = os.Getenv(SeedEnv)
+		}
+		if override == "" {
+			rootSeed = random.SecureRandomNumber(math.MinInt64, math.MaxInt64)
+			return
+		}
+		var err error
+		if rootSeed, err = strconv.ParseInt(override, 10, 64); err != nil {
+			panic("randtest: invalid seed " + strconv.Quote(override) + ": " + err.Error())
+		}
+	})
+	return rootSeed
+}
+
+// Seed returns the seed for the test, derived from the root seed and the test's name,
+// and arranges for the root seed to be logged if the test fails.
+func Seed(t testing.TB) int64 {
+	t.Helper()
+	root := RootSeed()
+	seed := ChildSeed(root, t.Name())
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("randtest: %s failed with seed %d; replay with -random.seed=%d or %s=%d", t.Name(), seed, root, SeedEnv, root)
+		}
+	})
+	return seed
+}
+
+// Rand returns a *math/rand.Rand for the test, seeded with Seed(t).
+// Each test and subtest gets an independent stream, which is the same every time
+// the test is run with the same root seed.
+// The *math/rand.Rand is not safe for concurrent use, so call Rand in each parallel subtest.
+func Rand(t testing.TB) *rand.Rand {
+	t.Helper()
+	return rand.New(rand.NewSource(Seed(t)))
+}
+
+// ChildSeed deterministically derives an independent seed from a parent seed and a name,
+// such as for each worker or each case in a table test.
+func ChildSeed(parent int64, name string) int64 {
+	h := fnv.New64a()
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(parent))
+	h.Write(b[:])
+	h.Write([]byte(name))
+	// SplitMix64 spreads the bits of the hash, so that seeds derived from similar names are unrelated
+	return int64(random.NewSplitMix64(h.Sum64()).Uint64())
+}
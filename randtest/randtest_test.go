@@ -0,0 +1,51 @@
+package randtest_test
+
+import (
+	"testing"
+
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestRandReproducible(t *testing.T) {
+	t.Parallel()
+	if randtest.Seed(t) != randtest.ChildSeed(randtest.RootSeed(), t.Name()) {
+		t.Error("Expecting the seed to be derived from the root seed and test name")
+	}
+	a, b := randtest.Rand(t), randtest.Rand(t)
+	for i := 0; i < 100; i++ {
+		if a.Int63() != b.Int63() {
+			t.Fatal("Expecting the same test to get the same stream")
+		}
+	}
+}
+
+func TestRandSubtestsIndependent(t *testing.T) {
+	t.Parallel()
+	seeds := make(chan int64, 10)
+	t.Run("group", func(t *testing.T) {
+		for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				seeds <- randtest.Rand(t).Int63()
+			})
+		}
+	})
+	close(seeds)
+	seen := map[int64]bool{}
+	for seed := range seeds {
+		seen[seed] = true
+	}
+	if len(seen) != 10 {
+		t.Errorf("Expecting each subtest to get a different stream; Got: %v", seen)
+	}
+}
+
+func TestChildSeed(t *testing.T) {
+	t.Parallel()
+	if randtest.ChildSeed(1, "a") != randtest.ChildSeed(1, "a") {
+		t.Error("Expecting child seeds to be deterministic")
+	}
+	if randtest.ChildSeed(1, "a") == randtest.ChildSeed(1, "b") || randtest.ChildSeed(1, "a") == randtest.ChildSeed(2, "a") {
+		t.Error("Expecting different parents or names to give different child seeds")
+	}
+}
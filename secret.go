@@ -0,0 +1,151 @@
+package random
+
+import (
+	"encoding/binary"
+	"errors"
+	"runtime"
+)
+
+// ErrLockedMemoryUnsupported is returned when locked memory is requested on a platform that does not support it.
+var ErrLockedMemoryUnsupported = errors.New("random: locked memory is not supported on this platform")
+
+// Secret holds sensitive random material, such as a key or password, in a []byte that is
+// wiped when Destroy is called, unlike a string, which can not be wiped and may be copied
+// around the heap by the runtime. All intermediate buffers used to create a Secret are zeroed.
+// Secrets in locked memory are also kept out of swap and core dumps, and are surrounded
+// by guard pages, so that reading or writing past either end crashes instead of leaking.
+// Secret's String method never returns the secret, so it is safe to log by accident.
+// A Secret is not safe for concurrent use with Destroy.
+type Secret struct {
+	b       []byte
+	free    func()          // releases locked memory, or nil for heap memory
+	cleanup runtime.Cleanup // calls free if the Secret is garbage collected without Destroy
+}
+
+// SecureRandomSecret uses crypto/rand to return a Secret of length random bytes, in heap memory.
+// If length is negative this will panic.
+func SecureRandomSecret(length int) *Secret {
+	s := &Secret{b: make([]byte, length)}
+	secureFill(s.b)
+	return s
+}
+
+// SecureRandomLockedSecret uses crypto/rand to return a Secret of length random bytes,
+// in memory that is locked and surrounded by guard pages.
+// It returns ErrLockedMemoryUnsupported on platforms other than Linux, or an error
+// if the memory could not be mapped or locked, such as when over RLIMIT_MEMLOCK.
+// Call Destroy as soon as the secret is no longer needed. If the Secret is garbage collected first,
+// its memory is wiped and released then, but that may be much later, or never if the program exits.
+// If length is negative this will panic.
+func SecureRandomLockedSecret(length int) (*Secret, error) {
+	s, err := newLockedSecret(length)
+	if err != nil {
+		return nil, err
+	}
+	secureFill(s.b)
+	return s, nil
+}
+
+// SecureRandomSecretStringBytes uses crypto/rand to return a Secret holding a random
+// password of given length made from the available character bytes, in heap memory.
+// If the available character bytes slice is empty or greater than 256 in length, or length is negative, this will panic.
+func SecureRandomSecretStringBytes(length int, availableCharBytes []byte) *Secret {
+	checkSecretStringBytes(length, availableCharBytes)
+	s := &Secret{b: make([]byte, length)}
+	secureFillStringBytes(s.b, availableCharBytes)
+	return s
+}
+
+// SecureRandomLockedSecretStringBytes uses crypto/rand to return a Secret holding a random
+// password of given length made from the available character bytes, in memory that is locked
+// and surrounded by guard pages. See SecureRandomLockedSecret for the errors returned.
+// If the available character bytes slice is empty or greater than 256 in length, or length is negative, this will panic.
+func SecureRandomLockedSecretStringBytes(length int, availableCharBytes []byte) (*Secret, error) {
+	checkSecretStringBytes(length, availableCharBytes)
+	s, err := newLockedSecret(length)
+	if err != nil {
+		return nil, err
+	}
+	secureFillStringBytes(s.b, availableCharBytes)
+	return s, nil
+}
+
+// checkSecretStringBytes panics if the length or available character bytes are invalid.
+func checkSecretStringBytes(length int, availableCharBytes []byte) {
+	if length < 0 {
+		panic("random: length can not be negative")
+	}
+	if len(availableCharBytes) == 0 || len(availableCharBytes) > 256 {
+		panic("random: availableCharBytes must not be empty or be longer than 256 bytes")
+	}
+}
+
+// newLockedSecret returns a Secret of the given length in locked memory.
+func newLockedSecret(length int) (*Secret, error) {
+	if length < 0 {
+		panic("random: length can not be negative")
+	}
+	b, free, err := allocLocked(length)
+	if err != nil {
+		return nil, err
+	}
+	s := &Secret{b: b, free: free}
+	s.cleanup = runtime.AddCleanup(s, func(free func()) { free() }, free)
+	return s, nil
+}
+
+// secureFill fills b with random data from crypto/rand, without any intermediate buffers.
+func secureFill(b []byte) {
+	secureRead(b)
+}
+
+// secureFillStringBytes fills b with random characters from availableCharBytes,
+// zeroing the random data used to pick them.
+func secureFillStringBytes(b []byte, availableCharBytes []byte) {
+	var buf [8]byte
+	indexer := newRandomIndexer(func() uint64 {
+		secureFill(buf[:])
+		return binary.LittleEndian.Uint64(buf[:])
+	}, uint64(len(availableCharBytes)))
+	for i := range b {
+		b[i] = availableCharBytes[indexer.next()]
+	}
+	clear(buf[:])
+	indexer.randomBits = 0
+}
+
+// Bytes returns the secret. The slice is only valid until Destroy is called, or until the
+// Secret is garbage collected, so do not keep it without the Secret, or copy it anywhere that will not be wiped.
+// After Destroy it returns nil.
+func (s *Secret) Bytes() []byte {
+	return s.b
+}
+
+// Len returns the length of the secret in bytes, or zero after Destroy.
+func (s *Secret) Len() int {
+	return len(s.b)
+}
+
+// Destroy zeroes the secret, and releases it if it is in locked memory.
+// It is safe to call more than once.
+func (s *Secret) Destroy() {
+	if s.b == nil {
+		return
+	}
+	clear(s.b)
+	if s.free != nil {
+		s.cleanup.Stop()
+		s.free()
+	}
+	s.b, s.free = nil, nil
+}
+
+// String implements fmt.Stringer without revealing the secret.
+func (s *Secret) String() string {
+	return "random.Secret(redacted)"
+}
+
+// GoString implements fmt.GoStringer without revealing the secret.
+func (s *Secret) GoString() string {
+	return s.String()
+}
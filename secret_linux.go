@@ -0,0 +1,46 @@
+//go:build linux
+
+package random
+
+import (
+	"os"
+	"syscall"
+)
+
+// madvDontDump is MADV_DONTDUMP, which keeps memory out of core dumps.
+const madvDontDump = 0x10
+
+// allocLocked maps length bytes of memory that is locked into RAM, excluded from core dumps,
+// and surrounded by inaccessible guard pages. The data is placed at the end of its pages,
+// so that overflows immediately hit the guard page after it.
+// The returned function zeroes, unlocks and unmaps the memory.
+func allocLocked(length int) ([]byte, func(), error) {
+	pageSize := os.Getpagesize()
+	dataSize := max(pageSize, (length+pageSize-1)/pageSize*pageSize)
+	region, err := syscall.Mmap(-1, 0, dataSize+2*pageSize, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE|syscall.MAP_ANON)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := region[pageSize : pageSize+dataSize]
+	free := func() {
+		clear(data)
+		_ = syscall.Munlock(data)
+		_ = syscall.Munmap(region)
+	}
+
+	if err = syscall.Mprotect(region[:pageSize], syscall.PROT_NONE); err == nil {
+		err = syscall.Mprotect(region[pageSize+dataSize:], syscall.PROT_NONE)
+	}
+	if err == nil {
+		err = syscall.Mlock(data)
+	}
+	if err != nil {
+		_ = syscall.Munmap(region)
+		return nil, nil, err
+	}
+	// Not all kernels support MADV_DONTDUMP, so this is best effort
+	_ = syscall.Madvise(data, madvDontDump)
+
+	// Cap the capacity, so that appending reallocates instead of writing into the guard page
+	return data[dataSize-length : dataSize : dataSize], free, nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package random
+
+// allocLocked is not supported on this platform.
+func allocLocked(length int) ([]byte, func(), error) {
+	return nil, nil, ErrLockedMemoryUnsupported
+}
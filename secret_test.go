@@ -0,0 +1,95 @@
+package random_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/veqryn/go-random"
+)
+
+func TestSecureRandomSecret(t *testing.T) {
+	t.Parallel()
+	s := random.SecureRandomSecret(32)
+	if s.Len() != 32 || bytes.Equal(s.Bytes(), make([]byte, 32)) {
+		t.Errorf("Expecting 32 random bytes; Got: %x", s.Bytes())
+	}
+	if out := fmt.Sprintf("%v %s %x %#v", s, s, s, s); bytes.Contains([]byte(out), s.Bytes()) || bytes.Contains([]byte(out), []byte(fmt.Sprintf("%x", s.Bytes()))) {
+		t.Errorf("Expecting formatting to not reveal the secret; Got: %s", out)
+	}
+
+	b := s.Bytes()
+	s.Destroy()
+	if !bytes.Equal(b, make([]byte, 32)) {
+		t.Errorf("Expecting the secret to be zeroed; Got: %x", b)
+	}
+	if s.Bytes() != nil || s.Len() != 0 {
+		t.Error("Expecting no bytes after Destroy")
+	}
+	s.Destroy()
+}
+
+func TestSecureRandomSecretStringBytes(t *testing.T) {
+	t.Parallel()
+	for length := 0; length <= 100; length++ {
+		s := random.SecureRandomSecretStringBytes(length, random.AlphaNumericBytes)
+		if s.Len() != length {
+			t.Errorf("Expecting length %d; Got: %d", length, s.Len())
+		}
+		for _, c := range s.Bytes() {
+			if bytes.IndexByte(random.AlphaNumericBytes, c) < 0 {
+				t.Errorf("Expecting only alphanumeric characters; Got: %q", s.Bytes())
+			}
+		}
+		s.Destroy()
+	}
+}
+
+func TestSecureRandomLockedSecret(t *testing.T) {
+	t.Parallel()
+	s, err := random.SecureRandomLockedSecret(48)
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, random.ErrLockedMemoryUnsupported) {
+			t.Errorf("Expecting ErrLockedMemoryUnsupported; Got: %v", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Skipf("Locked memory is not available, likely due to RLIMIT_MEMLOCK: %v", err)
+	}
+	if s.Len() != 48 || bytes.Equal(s.Bytes(), make([]byte, 48)) {
+		t.Errorf("Expecting 48 random bytes; Got: %x", s.Bytes())
+	}
+	// Appending must reallocate rather than write into the guard page after the data
+	if cap(s.Bytes()) != 48 {
+		t.Errorf("Expecting capacity 48; Got: %d", cap(s.Bytes()))
+	}
+	if appended := append(s.Bytes(), 1); len(appended) != 49 {
+		t.Errorf("Expecting 49 bytes after append; Got: %d", len(appended))
+	}
+	s.Destroy()
+	s.Destroy()
+
+	// Secrets that are garbage collected without Destroy are released, so do not use up RLIMIT_MEMLOCK
+	for i := 0; i < 3000; i++ {
+		if _, err = random.SecureRandomLockedSecret(4096); err != nil {
+			t.Fatalf("Expecting unreachable secrets to be released; Got: %v after %d", err, i)
+		}
+		if i%100 == 99 {
+			runtime.GC()
+		}
+	}
+
+	s, err = random.SecureRandomLockedSecretStringBytes(5000, random.HexBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range s.Bytes() {
+		if bytes.IndexByte(random.HexBytes, c) < 0 {
+			t.Fatalf("Expecting only hex characters; Got: %q", c)
+		}
+	}
+	s.Destroy()
+}
@@ -0,0 +1,191 @@
+package random
+
+import (
+	"iter"
+	math_rand "math/rand"
+)
+
+// SecureRandomElement uses crypto/rand to return a uniformly random element of the slice.
+// If the slice is empty, this will panic.
+func SecureRandomElement[T any](s []T) T {
+	return randomElementBase(SecureRandSource.Uint64, s)
+}
+
+// PseudoRandomElement uses math/rand to return a uniformly random element of the slice.
+// If the slice is empty, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomElement[T any](s []T) T {
+	return randomElementBase(math_rand.Uint64, s)
+}
+
+// PseudoRandomElementRand uses math/rand to return a uniformly random element of the slice.
+// If the slice is empty, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomElementRand[T any](rand *math_rand.Rand, s []T) T {
+	return randomElementBase(rand.Uint64, s)
+}
+
+// randomElementBase returns a uniformly random element of the slice.
+func randomElementBase[T any](randUint64 func() uint64, s []T) T {
+	if len(s) == 0 {
+		panic("random: slice must not be empty")
+	}
+	return s[newRandomIndexer(randUint64, uint64(len(s))).next()]
+}
+
+// SecureRandomKey uses crypto/rand to return a uniformly random key of the map.
+// Go's map iteration order is not uniformly random, so this takes time proportional to the size of the map.
+// If the map is empty, this will panic.
+func SecureRandomKey[K comparable, V any](m map[K]V) K {
+	return randomKeyBase(SecureRandSource.Uint64, m)
+}
+
+// PseudoRandomKey uses math/rand to return a uniformly random key of the map.
+// Go's map iteration order is not uniformly random, so this takes time proportional to the size of the map.
+// If the map is empty, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomKey[K comparable, V any](m map[K]V) K {
+	return randomKeyBase(math_rand.Uint64, m)
+}
+
+// PseudoRandomKeyRand uses math/rand to return a uniformly random key of the map.
+// Go's map iteration order is not uniformly random, so this takes time proportional to the size of the map.
+// If the map is empty, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomKeyRand[K comparable, V any](rand *math_rand.Rand, m map[K]V) K {
+	return randomKeyBase(rand.Uint64, m)
+}
+
+// randomKeyBase returns the key at a uniformly random position in the map's iteration order.
+func randomKeyBase[K comparable, V any](randUint64 func() uint64, m map[K]V) K {
+	if len(m) == 0 {
+		panic("random: map must not be empty")
+	}
+	i := newRandomIndexer(randUint64, uint64(len(m))).next()
+	for k := range m {
+		if i == 0 {
+			return k
+		}
+		i--
+	}
+	panic("random: map modified concurrently") // Impossible
+}
+
+// SecureSampleSeq uses crypto/rand to return k values chosen uniformly from the sequence,
+// in random order, using reservoir sampling so that only k values are kept in memory.
+// If the sequence has k or fewer values, all of them are returned.
+// Use it for channels with a sequence like: func(yield func(T) bool) { for v := range ch { if !yield(v) { return } } }
+// If k is negative, this will panic.
+func SecureSampleSeq[T any](seq iter.Seq[T], k int) []T {
+	return sampleSeqBase(SecureRandSource.Uint64, seq, k)
+}
+
+// PseudoSampleSeq uses math/rand to return k values chosen uniformly from the sequence,
+// in random order, using reservoir sampling so that only k values are kept in memory.
+// If the sequence has k or fewer values, all of them are returned.
+// If k is negative, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoSampleSeq[T any](seq iter.Seq[T], k int) []T {
+	return sampleSeqBase(math_rand.Uint64, seq, k)
+}
+
+// PseudoSampleSeqRand uses math/rand to return k values chosen uniformly from the sequence,
+// in random order, using reservoir sampling so that only k values are kept in memory.
+// If the sequence has k or fewer values, all of them are returned.
+// If k is negative, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoSampleSeqRand[T any](rand *math_rand.Rand, seq iter.Seq[T], k int) []T {
+	return sampleSeqBase(rand.Uint64, seq, k)
+}
+
+// sampleSeqBase implements reservoir sampling (Algorithm R): the i-th value replaces a
+// random value in the reservoir with probability k / (i + 1). The reservoir is then
+// shuffled, because the first k values would otherwise keep their original order.
+func sampleSeqBase[T any](randUint64 func() uint64, seq iter.Seq[T], k int) []T {
+	if k < 0 {
+		panic("random: k can not be negative")
+	}
+	reservoir := make([]T, 0, k)
+	if k == 0 {
+		return reservoir
+	}
+
+	// One indexer is used for every index, so that unused random bits are not thrown away
+	indexer := newRandomIndexer(randUint64, 1)
+	i := uint64(0)
+	for v := range seq {
+		if len(reservoir) < k {
+			reservoir = append(reservoir, v)
+		} else {
+			indexer.setN(i + 1)
+			if j := indexer.next(); j < uint64(k) {
+				reservoir[j] = v
+			}
+		}
+		i++
+	}
+	for j := len(reservoir) - 1; j > 0; j-- {
+		indexer.setN(uint64(j + 1))
+		swap := indexer.next()
+		reservoir[j], reservoir[swap] = reservoir[swap], reservoir[j]
+	}
+	return reservoir
+}
+
+// SecureRandomizedRange uses crypto/rand to return a sequence of the integers [0, n) in a random order.
+// The order is generated lazily by a keyed permutation, so it uses constant memory regardless of n,
+// and each value takes constant time on average. The order is chosen when this is called,
+// so the sequence gives the same order each time it is iterated.
+// If n is negative, this will panic.
+func SecureRandomizedRange(n int) iter.Seq[int] {
+	return randomizedRangeBase(secureFill, n)
+}
+
+// PseudoRandomizedRange uses math/rand to return a sequence of the integers [0, n) in a random order.
+// The order is generated lazily by a keyed permutation, so it uses constant memory regardless of n,
+// and each value takes constant time on average. The order is chosen when this is called,
+// so the sequence gives the same order each time it is iterated.
+// If n is negative, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomizedRange(n int) iter.Seq[int] {
+	return randomizedRangeBase(pseudoFill(math_rand.Uint64), n)
+}
+
+// PseudoRandomizedRangeRand uses math/rand to return a sequence of the integers [0, n) in a random order.
+// The order is generated lazily by a keyed permutation, so it uses constant memory regardless of n,
+// and each value takes constant time on average. The order is chosen when this is called,
+// so the sequence gives the same order each time it is iterated.
+// If n is negative, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomizedRangeRand(rand *math_rand.Rand, n int) iter.Seq[int] {
+	return randomizedRangeBase(pseudoFill(rand.Uint64), n)
+}
+
+// randomizedRangeBase returns a sequence of [0, n) permuted by a Feistel network with a random key.
+func randomizedRangeBase(fill func([]byte), n int) iter.Seq[int] {
+	if n < 0 {
+		panic("random: n can not be negative")
+	}
+	if n == 0 {
+		return func(yield func(int) bool) {}
+	}
+	key := make([]byte, 16)
+	fill(key)
+	permutation := newFeistel(key, uint64(n))
+	clear(key)
+	return func(yield func(int) bool) {
+		for i := uint64(0); i < uint64(n); i++ {
+			if !yield(int(permutation.encrypt(i))) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package random_test
+
+import (
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestRandomElement(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	s := []string{"a", "b", "c"}
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		counts[random.PseudoRandomElementRand(source, s)]++
+	}
+	for _, v := range s {
+		if counts[v] < 850 || counts[v] > 1150 {
+			t.Errorf("Expecting roughly 1000 of %s; Got: %v", v, counts)
+		}
+	}
+	if v := random.SecureRandomElement([]int{7}); v != 7 {
+		t.Errorf("Expecting 7; Got: %d", v)
+	}
+}
+
+func TestRandomKey(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	m := map[int]bool{1: true, 2: true, 3: true, 4: true}
+	counts := map[int]int{}
+	for i := 0; i < 4000; i++ {
+		counts[random.PseudoRandomKeyRand(source, m)]++
+		if k := random.SecureRandomKey(m); !m[k] {
+			t.Errorf("Expecting a key of the map; Got: %d", k)
+		}
+	}
+	for k := range m {
+		if counts[k] < 850 || counts[k] > 1150 {
+			t.Errorf("Expecting roughly 1000 of %d; Got: %v", k, counts)
+		}
+	}
+}
+
+func TestSampleSeq(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	counts := make([]int, 10)
+	for i := 0; i < 5000; i++ {
+		sample := random.PseudoSampleSeqRand(source, slices.Values([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}), 3)
+		if len(sample) != 3 {
+			t.Fatalf("Expecting 3 values; Got: %v", sample)
+		}
+		slices.Sort(sample)
+		if len(slices.Compact(sample)) != 3 {
+			t.Errorf("Expecting distinct values; Got: %v", sample)
+		}
+		for _, v := range sample {
+			counts[v]++
+		}
+	}
+	// Each value is chosen with probability 3/10
+	for v, count := range counts {
+		if count < 1350 || count > 1650 {
+			t.Errorf("Expecting roughly 1500 of %d; Got: %v", v, counts)
+		}
+	}
+
+	// Unused random bits are kept for the next index, rather than drawing a new value for each
+	counter := &countingSource{Source64: source}
+	random.PseudoSampleSeqRand(rand.New(counter), slices.Values(make([]int, 10_000)), 10)
+	if counter.calls > 5000 {
+		t.Errorf("Expecting fewer random values than sequence values; Got: %d", counter.calls)
+	}
+
+	if sample := random.SecureSampleSeq(maps.Keys(map[string]int{"a": 1, "b": 2}), 5); len(sample) != 2 {
+		t.Errorf("Expecting every value of a short sequence; Got: %v", sample)
+	}
+	if sample := random.PseudoSampleSeq(slices.Values([]int{1, 2}), 0); len(sample) != 0 {
+		t.Errorf("Expecting no values; Got: %v", sample)
+	}
+}
+
+// countingSource counts the calls to Uint64.
+type countingSource struct {
+	rand.Source64
+	calls int
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.calls++
+	return s.Source64.Uint64()
+}
+
+func TestRandomizedRange(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	for _, n := range []int{0, 1, 2, 3, 17, 1000, 4097} {
+		seq := random.PseudoRandomizedRangeRand(source, n)
+		values := slices.Collect(seq)
+		if !slices.Equal(values, slices.Collect(seq)) {
+			t.Errorf("Expecting the same order each iteration")
+		}
+		sorted := slices.Sorted(slices.Values(values))
+		for i, v := range sorted {
+			if v != i {
+				t.Fatalf("Expecting a permutation of [0, %d); Got: %v", n, values)
+			}
+		}
+		if len(sorted) != n {
+			t.Errorf("Expecting %d values; Got: %d", n, len(sorted))
+		}
+		if n >= 1000 && slices.IsSorted(values) {
+			t.Errorf("Expecting a random order; Got: %v", values)
+		}
+	}
+
+	// Stopping early works, and large ranges do not allocate the whole range
+	count := 0
+	for range random.SecureRandomizedRange(math.MaxInt) {
+		if count++; count == 10 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Errorf("Expecting to stop after 10; Got: %d", count)
+	}
+}
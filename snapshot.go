@@ -0,0 +1,120 @@
+package random
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+)
+
+// ErrSnapshotUnsupported is returned by Snapshot and Restore for sources that do not
+// implement encoding.BinaryMarshaler and encoding.BinaryUnmarshaler.
+var ErrSnapshotUnsupported = errors.New("random: source does not support snapshots")
+
+// Snapshot returns the current state of the source, so that a long running job can
+// later Restore it and continue with exactly the same sequence of values.
+//
+// The reproducible sources in this package support snapshots: SplitMix64, Xoshiro256StarStar,
+// Xoshiro256Plus, PCG64 and RecordingSource. The sources created by math/rand.NewSource do not,
+// so wrap them with NewRecordingSource instead. SecureRandSource and ConcurrentRandSource are
+// not reproducible, so have no state to snapshot.
+//
+// A *math/rand.Rand keeps leftover bytes between calls to its Read method, which is used by
+// PseudoRandomBytesRand, and those bytes are not part of the source's state. To restore exactly,
+// either avoid Read, or snapshot only between calls to Read whose lengths are multiples of 7.
+func Snapshot(source rand.Source) ([]byte, error) {
+	marshaler, ok := source.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, ErrSnapshotUnsupported
+	}
+	return marshaler.MarshalBinary()
+}
+
+// Restore sets the state of the source to one returned by Snapshot for a source of the same type.
+// Create a new *math/rand.Rand with the restored source, rather than reusing one that was
+// already drawn from, so that no leftover bytes from Read are carried over.
+func Restore(source rand.Source, state []byte) error {
+	unmarshaler, ok := source.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return ErrSnapshotUnsupported
+	}
+	return unmarshaler.UnmarshalBinary(state)
+}
+
+// RecordingSource wraps a math/rand source, counting how many values have been drawn from it,
+// so that the stream can be recreated from its seed and fast-forwarded to the same position.
+// It implements math/rand.Source64, encoding.BinaryMarshaler and encoding.BinaryUnmarshaler,
+// so works with Snapshot and Restore, and gives exactly the same values as rand.NewSource(seed).
+// Fast-forwarding takes time proportional to the number of draws (a few nanoseconds each), so for
+// very long streams prefer a source that marshals its state directly, such as Xoshiro256StarStar.
+// Not cryptographically secure. Not safe for concurrent use.
+type RecordingSource struct {
+	seed   int64
+	draws  uint64
+	source rand.Source64
+}
+
+// NewRecordingSource returns a RecordingSource for rand.NewSource(seed).
+func NewRecordingSource(seed int64) *RecordingSource {
+	return &RecordingSource{seed: seed, source: rand.NewSource(seed).(rand.Source64)}
+}
+
+// Uint64 allows implementation of math/rand.Source64
+func (s *RecordingSource) Uint64() uint64 {
+	s.draws++
+	return s.source.Uint64()
+}
+
+// Int63 allows implementation of math/rand.Source
+func (s *RecordingSource) Int63() int64 {
+	s.draws++
+	return s.source.Int63()
+}
+
+// Seed allows implementation of math/rand.Source.
+// It restarts the stream and the count of draws.
+func (s *RecordingSource) Seed(seed int64) {
+	s.seed, s.draws = seed, 0
+	s.source.Seed(seed)
+}
+
+// Draws returns how many values have been drawn from the source since it was seeded.
+func (s *RecordingSource) Draws() uint64 {
+	return s.draws
+}
+
+// Seek moves the source to the position after the given number of draws, such as a value
+// previously returned by Draws. It fast-forwards from the current position if it is before
+// the target, otherwise it restarts the stream from the seed.
+func (s *RecordingSource) Seek(draws uint64) {
+	if draws < s.draws {
+		s.Seed(s.seed)
+	}
+	for ; s.draws < draws; s.draws++ {
+		// Both Int63 and Uint64 of the math/rand source advance it by one value
+		s.source.Uint64()
+	}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (s *RecordingSource) MarshalBinary() ([]byte, error) {
+	b := binary.BigEndian.AppendUint64([]byte(recordingSourceMagic), uint64(s.seed))
+	return binary.BigEndian.AppendUint64(b, s.draws), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It recreates the stream from the seed and fast-forwards it, see Seek.
+func (s *RecordingSource) UnmarshalBinary(data []byte) error {
+	state, err := unmarshalState(data, recordingSourceMagic, 2)
+	if err != nil {
+		return err
+	}
+	if s.source == nil {
+		s.source = rand.NewSource(int64(state[0])).(rand.Source64)
+	} else {
+		s.source.Seed(int64(state[0]))
+	}
+	s.seed, s.draws = int64(state[0]), 0
+	s.Seek(state[1])
+	return nil
+}
@@ -0,0 +1,87 @@
+package random_test
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+// draw uses the source the way a simulation would, through the Pseudo*Rand functions.
+func draw(r *rand.Rand) []any {
+	return []any{
+		random.PseudoRandomStringRand(r, 20),
+		random.PseudoRandomInt63Rand(r, -1000, 1000),
+		random.PseudoRandomStringBytesRand(r, 13, random.HexBytes),
+		r.Float64(),
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Parallel()
+	seed := randtest.Seed(t)
+	tests := []struct {
+		source, restored rand.Source
+	}{
+		{random.NewRecordingSource(seed), &random.RecordingSource{}},
+		{random.NewXoshiro256StarStar(uint64(seed)), &random.Xoshiro256StarStar{}},
+		{random.NewPCG64(uint64(seed), 3), &random.PCG64{}},
+	}
+	for _, tc := range tests {
+		r := rand.New(tc.source)
+		for i := 0; i < 50; i++ {
+			draw(r)
+		}
+		state, err := random.Snapshot(tc.source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := [][]any{draw(r), draw(r), draw(r)}
+
+		if err = random.Restore(tc.restored, state); err != nil {
+			t.Fatal(err)
+		}
+		restored := rand.New(tc.restored)
+		for i, e := range expected {
+			if got := draw(restored); !slices.Equal(e, got) {
+				t.Errorf("Expecting restored %T to give %v at %d; Got: %v", tc.source, e, i, got)
+			}
+		}
+	}
+}
+
+func TestSnapshotUnsupported(t *testing.T) {
+	t.Parallel()
+	if _, err := random.Snapshot(rand.NewSource(1)); !errors.Is(err, random.ErrSnapshotUnsupported) {
+		t.Errorf("Expecting ErrSnapshotUnsupported; Got: %v", err)
+	}
+	if err := random.Restore(random.SecureRandSource, nil); !errors.Is(err, random.ErrSnapshotUnsupported) {
+		t.Errorf("Expecting ErrSnapshotUnsupported; Got: %v", err)
+	}
+}
+
+func TestRecordingSourceSeek(t *testing.T) {
+	t.Parallel()
+	seed := randtest.Seed(t)
+	plain := rand.New(rand.NewSource(seed))
+	recording := random.NewRecordingSource(seed)
+	r := rand.New(recording)
+	for i := 0; i < 100; i++ {
+		if a, b := plain.Int63(), r.Int63(); a != b {
+			t.Fatalf("Expecting the same values as rand.NewSource; Got: %d and %d", a, b)
+		}
+	}
+	if recording.Draws() != 100 {
+		t.Errorf("Expecting 100 draws; Got: %d", recording.Draws())
+	}
+	next := plain.Uint64()
+
+	recording.Seek(10)
+	recording.Seek(100)
+	if got := recording.Uint64(); got != next {
+		t.Errorf("Expecting seek to return to the same position; Got: %d and %d", next, got)
+	}
+}
@@ -0,0 +1,257 @@
+package random
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// SqidsDefaultAlphabet is the alphabet used by Sqids when none is given,
+// which is the same characters as AlphaNumeric in a different order.
+const SqidsDefaultAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// ErrSqidsMaxAttempts is returned by Sqids.Encode when every variation of an ID is blocked.
+var ErrSqidsMaxAttempts = errors.New("random: reached max attempts to re-generate the ID")
+
+// SqidsOptions configures a Sqids encoder.
+type SqidsOptions struct {
+	// Alphabet is the characters IDs are made from, such as AlphaNumeric or Base64URL.
+	// It must have at least 3 unique single byte characters. If empty, SqidsDefaultAlphabet is used.
+	Alphabet string
+
+	// Salt, if not empty, shuffles the alphabet with Xoshiro256StarStar seeded from the salt,
+	// so that IDs are different from those of other applications. This is obfuscation, not encryption:
+	// a salted alphabet can be recovered from enough IDs. Use Permutation to hide IDs from determined users.
+	// Other Sqids implementations give the same IDs when given the shuffled Alphabet method's result.
+	Salt string
+
+	// MinLength is the minimum length of IDs, which are padded if shorter. It must be between 0 and 255.
+	MinLength int
+
+	// Blocklist is the words that IDs must not contain, matched following the Sqids rules, rather than
+	// those of Blocklist. If nil, DefaultBlocklistWords is used. Use an empty slice for no blocklist.
+	Blocklist []string
+}
+
+// Sqids encodes lists of numbers into short, unique, URL-safe IDs, and decodes them back, following
+// the Sqids specification (https://sqids.org), so that IDs are interoperable with other implementations
+// given the same alphabet, minimum length and blocklist. It is safe for concurrent use.
+type Sqids struct {
+	alphabet  []byte // before the Sqids shuffle, for interoperability
+	shuffled  []byte // after the Sqids shuffle, used for encoding
+	minLength int
+	blocklist []string
+}
+
+// NewSqids returns a Sqids encoder with the options.
+// If the options are invalid, this will panic.
+func NewSqids(opts SqidsOptions) *Sqids {
+	alphabet := opts.Alphabet
+	if alphabet == "" {
+		alphabet = SqidsDefaultAlphabet
+	}
+	if len(alphabet) < 3 {
+		panic("random: sqids alphabet must have at least 3 characters")
+	}
+	seen := map[byte]bool{}
+	for i := 0; i < len(alphabet); i++ {
+		if alphabet[i] >= 0x80 {
+			panic("random: sqids alphabet must not contain multibyte characters")
+		}
+		if seen[alphabet[i]] {
+			panic("random: sqids alphabet must not contain duplicates")
+		}
+		seen[alphabet[i]] = true
+	}
+	if opts.MinLength < 0 || opts.MinLength > 255 {
+		panic("random: sqids min length must be between 0 and 255")
+	}
+
+	chars := []byte(alphabet)
+	if opts.Salt != "" {
+		sum := sha256.Sum256([]byte(opts.Salt))
+		x := NewXoshiro256StarStar(binary.BigEndian.Uint64(sum[:]))
+		for i := len(chars) - 1; i > 0; i-- {
+			j := newRandomIndexer(x.Uint64, uint64(i+1)).next()
+			chars[i], chars[j] = chars[j], chars[i]
+		}
+	}
+
+	words := opts.Blocklist
+	if words == nil {
+		words = DefaultBlocklistWords()
+	}
+	// Only keep words that could appear in an ID, lower cased
+	lower := strings.ToLower(string(chars))
+	var blocklist []string
+	for _, word := range words {
+		word = strings.ToLower(word)
+		if len(word) >= 3 && strings.Trim(word, lower) == "" {
+			blocklist = append(blocklist, word)
+		}
+	}
+
+	return &Sqids{alphabet: chars, shuffled: sqidsShuffle(chars), minLength: opts.MinLength, blocklist: blocklist}
+}
+
+// Alphabet returns the alphabet after salting, which gives the same IDs when used with other Sqids implementations.
+func (s *Sqids) Alphabet() string {
+	return string(s.alphabet)
+}
+
+// Encode returns the ID for the numbers. An empty list of numbers gives an empty ID.
+// It returns ErrSqidsMaxAttempts if every variation of the ID is blocked, which is only
+// likely with a small alphabet and a large blocklist.
+func (s *Sqids) Encode(numbers ...uint64) (string, error) {
+	if len(numbers) == 0 {
+		return "", nil
+	}
+	for increment := 0; increment <= len(s.shuffled); increment++ {
+		if id := s.encode(numbers, increment); !s.isBlocked(id) {
+			return id, nil
+		}
+	}
+	return "", ErrSqidsMaxAttempts
+}
+
+// encode returns the ID for the numbers, with the alphabet offset further by increment,
+// which gives a different ID for the same numbers when one is blocked.
+func (s *Sqids) encode(numbers []uint64, increment int) string {
+	size := len(s.shuffled)
+	offset := len(numbers)
+	for i, n := range numbers {
+		offset += int(s.shuffled[n%uint64(size)]) + i
+	}
+	offset = (offset + increment) % size
+
+	alphabet := append(append(make([]byte, 0, size), s.shuffled[offset:]...), s.shuffled[:offset]...)
+	prefix := alphabet[0]
+	reverseBytes(alphabet)
+
+	id := []byte{prefix}
+	for i, n := range numbers {
+		id = append(id, sqidsToID(n, alphabet[1:])...)
+		if i < len(numbers)-1 {
+			id = append(id, alphabet[0])
+			alphabet = sqidsShuffle(alphabet)
+		}
+	}
+
+	if len(id) < s.minLength {
+		id = append(id, alphabet[0])
+		for len(id) < s.minLength {
+			alphabet = sqidsShuffle(alphabet)
+			id = append(id, alphabet[:min(s.minLength-len(id), size)]...)
+		}
+	}
+	return string(id)
+}
+
+// Decode returns the numbers in the ID, or nil if the ID is invalid.
+// Only the one canonical ID for each list of numbers is valid, so that the same numbers can not be
+// referred to by different IDs.
+func (s *Sqids) Decode(id string) []uint64 {
+	if id == "" {
+		return nil
+	}
+	offset := strings.IndexByte(string(s.shuffled), id[0])
+	if offset < 0 {
+		return nil
+	}
+	alphabet := append(append(make([]byte, 0, len(s.shuffled)), s.shuffled[offset:]...), s.shuffled[:offset]...)
+	reverseBytes(alphabet)
+
+	var numbers []uint64
+	for rest := id[1:]; rest != ""; {
+		chunk, remaining, found := strings.Cut(rest, string(alphabet[0]))
+		if chunk == "" {
+			// The padding after the numbers, when shorter than the minimum length
+			break
+		}
+		n, ok := sqidsToNumber(chunk, alphabet[1:])
+		if !ok {
+			return nil
+		}
+		numbers = append(numbers, n)
+		if found {
+			alphabet = sqidsShuffle(alphabet)
+		}
+		rest = remaining
+	}
+
+	if canonical, err := s.Encode(numbers...); err != nil || canonical != id {
+		return nil
+	}
+	return numbers
+}
+
+// isBlocked returns true if the ID contains a blocklist word, following the Sqids rules:
+// short IDs and words must match exactly, words with digits must be at the start or end,
+// and other words can be anywhere.
+func (s *Sqids) isBlocked(id string) bool {
+	id = strings.ToLower(id)
+	for _, word := range s.blocklist {
+		switch {
+		case len(word) > len(id):
+			continue
+		case len(id) <= 3 || len(word) <= 3:
+			if id == word {
+				return true
+			}
+		case strings.ContainsAny(word, "0123456789"):
+			if strings.HasPrefix(id, word) || strings.HasSuffix(id, word) {
+				return true
+			}
+		case strings.Contains(id, word):
+			return true
+		}
+	}
+	return false
+}
+
+// sqidsShuffle returns a copy of the alphabet in the Sqids deterministic order.
+func sqidsShuffle(alphabet []byte) []byte {
+	chars := append([]byte(nil), alphabet...)
+	for i, j := 0, len(chars)-1; j > 0; i, j = i+1, j-1 {
+		r := (i*j + int(chars[i]) + int(chars[j])) % len(chars)
+		chars[i], chars[r] = chars[r], chars[i]
+	}
+	return chars
+}
+
+// sqidsToID writes n using the alphabet as digits.
+func sqidsToID(n uint64, alphabet []byte) []byte {
+	var id []byte
+	base := uint64(len(alphabet))
+	for {
+		id = append(id, alphabet[n%base])
+		if n /= base; n == 0 {
+			break
+		}
+	}
+	reverseBytes(id)
+	return id
+}
+
+// sqidsToNumber reads the chunk using the alphabet as digits,
+// returning false if it has other characters or overflows a uint64.
+func sqidsToNumber(chunk string, alphabet []byte) (uint64, bool) {
+	base := uint64(len(alphabet))
+	n := uint64(0)
+	for i := 0; i < len(chunk); i++ {
+		idx := strings.IndexByte(string(alphabet), chunk[i])
+		if idx < 0 || n > (^uint64(0)-uint64(idx))/base {
+			return 0, false
+		}
+		n = n*base + uint64(idx)
+	}
+	return n, true
+}
+
+// reverseBytes reverses b in place.
+func reverseBytes(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
@@ -0,0 +1,83 @@
+package random_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+// Test vectors from the Sqids specification
+func TestSqidsSpec(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		opts     random.SqidsOptions
+		numbers  []uint64
+		expected string
+	}{
+		{random.SqidsOptions{Blocklist: []string{}}, []uint64{1, 2, 3}, "86Rf07"},
+		{random.SqidsOptions{Blocklist: []string{}}, []uint64{0}, "bM"},
+		{random.SqidsOptions{Blocklist: []string{}}, []uint64{1}, "Uk"},
+		{random.SqidsOptions{Blocklist: []string{}}, []uint64{4572721}, "aho1e"},
+		{random.SqidsOptions{Blocklist: []string{"aho1e"}}, []uint64{4572721}, "JExTR"},
+		{random.SqidsOptions{Alphabet: "0123456789abcdef", Blocklist: []string{}}, []uint64{1, 2, 3}, "489158"},
+		{random.SqidsOptions{MinLength: 62, Blocklist: []string{}}, []uint64{1, 2, 3}, "86Rf07xd4zBmiJXQG6otHEbew02c3PWsUOLZxADhCpKj7aVFv9I8RquYrNlSTM"},
+	}
+	for _, tc := range tests {
+		s := random.NewSqids(tc.opts)
+		id, err := s.Encode(tc.numbers...)
+		if err != nil || id != tc.expected {
+			t.Errorf("Expecting %v to encode to %s; Got: %s %v", tc.numbers, tc.expected, id, err)
+		}
+		if decoded := s.Decode(tc.expected); !slices.Equal(decoded, tc.numbers) {
+			t.Errorf("Expecting %s to decode to %v; Got: %v", tc.expected, tc.numbers, decoded)
+		}
+	}
+}
+
+func TestSqidsRoundTrip(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	salted := random.NewSqids(random.SqidsOptions{Alphabet: random.Base64URL, Salt: "secret", MinLength: 8})
+	for _, s := range []*random.Sqids{random.NewSqids(random.SqidsOptions{}), salted} {
+		for i := 0; i < 500; i++ {
+			numbers := make([]uint64, source.Intn(4)+1)
+			for j := range numbers {
+				numbers[j] = source.Uint64() >> source.Intn(64)
+			}
+			id, err := s.Encode(numbers...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded := s.Decode(id); !slices.Equal(decoded, numbers) {
+				t.Errorf("Expecting %s to decode to %v; Got: %v", id, numbers, decoded)
+			}
+		}
+	}
+
+	// Salting changes the IDs, and is interoperable through the salted alphabet
+	plain := random.NewSqids(random.SqidsOptions{Alphabet: random.Base64URL, MinLength: 8})
+	a, _ := salted.Encode(42)
+	b, _ := plain.Encode(42)
+	if a == b {
+		t.Errorf("Expecting the salt to change IDs; Got: %s", a)
+	}
+	unsalted := random.NewSqids(random.SqidsOptions{Alphabet: salted.Alphabet(), MinLength: 8})
+	if c, _ := unsalted.Encode(42); c != a {
+		t.Errorf("Expecting the salted alphabet to give the same IDs; Got: %s and %s", a, c)
+	}
+	if len(a) < 8 {
+		t.Errorf("Expecting at least 8 characters; Got: %s", a)
+	}
+}
+
+func TestSqidsDecodeInvalid(t *testing.T) {
+	t.Parallel()
+	s := random.NewSqids(random.SqidsOptions{})
+	for _, id := range []string{"", "*", "86Rf07*", "86Rf07x"} {
+		if decoded := s.Decode(id); decoded != nil {
+			t.Errorf("Expecting %q to be invalid; Got: %v", id, decoded)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package random
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"time"
+)
+
+// TimeConstraints restricts the times and dates that can be randomly chosen,
+// such as to business hours on weekdays in a given time zone.
+// The zero value allows any time on any day in UTC.
+type TimeConstraints struct {
+	// Location is the time zone that days, weekdays and times of day are in.
+	// If nil, UTC is used.
+	Location *time.Location
+
+	// Weekdays are the days of the week allowed. If empty, all days are allowed.
+	Weekdays []time.Weekday
+
+	// StartOfDay and EndOfDay are the wall clock time of day window allowed, [StartOfDay, EndOfDay),
+	// such as 9*time.Hour and 17*time.Hour for business hours.
+	// If EndOfDay is zero, the window ends at midnight.
+	StartOfDay time.Duration
+	EndOfDay   time.Duration
+}
+
+// SecureRandomTime uses crypto/rand to return a time between [start, end).
+// If end is not after start, or the difference overflows time.Duration (about 292 years), this will panic.
+func SecureRandomTime(start, end time.Time) time.Time {
+	return start.Add(time.Duration(SecureRandomNumber(0, int64(timeSpan(start, end)))))
+}
+
+// PseudoRandomTime uses math/rand to return a time between [start, end).
+// If end is not after start, or the difference overflows time.Duration (about 292 years), this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomTime(start, end time.Time) time.Time {
+	return start.Add(time.Duration(PseudoRandomInt63(0, int64(timeSpan(start, end)))))
+}
+
+// PseudoRandomTimeRand uses math/rand to return a time between [start, end).
+// If end is not after start, or the difference overflows time.Duration (about 292 years), this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomTimeRand(rand *rand.Rand, start, end time.Time) time.Time {
+	return start.Add(time.Duration(PseudoRandomInt63Rand(rand, 0, int64(timeSpan(start, end)))))
+}
+
+// timeSpan returns the duration between start and end.
+// If end is not after start, or the difference overflows time.Duration, this will panic.
+func timeSpan(start, end time.Time) time.Duration {
+	span := end.Sub(start)
+	if span <= 0 {
+		panic("random: end must be after start")
+	}
+	if !start.Add(span).Equal(end) {
+		panic("random: time range must not overflow time.Duration")
+	}
+	return span
+}
+
+// SecureRandomDuration uses crypto/rand to return a duration between [minInclusive, maxExclusive).
+// If max - min <= 0, this will panic.
+func SecureRandomDuration(minInclusive, maxExclusive time.Duration) time.Duration {
+	if maxExclusive <= minInclusive {
+		panic("random: maxExclusive must be greater than minInclusive")
+	}
+	return time.Duration(SecureRandomNumber(int64(minInclusive), int64(maxExclusive)))
+}
+
+// PseudoRandomDuration uses math/rand to return a duration between [minInclusive, maxExclusive).
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure. If max - min <= 0, or max - min overflows int64, this panics.
+func PseudoRandomDuration(minInclusive, maxExclusive time.Duration) time.Duration {
+	return time.Duration(PseudoRandomInt63(int64(minInclusive), int64(maxExclusive)))
+}
+
+// PseudoRandomDurationRand uses math/rand to return a duration between [minInclusive, maxExclusive).
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure. If max - min <= 0, or max - min overflows int64, this panics.
+func PseudoRandomDurationRand(rand *rand.Rand, minInclusive, maxExclusive time.Duration) time.Duration {
+	return time.Duration(PseudoRandomInt63Rand(rand, int64(minInclusive), int64(maxExclusive)))
+}
+
+// SecureRandomTimeConstrained uses crypto/rand to return a time between [start, end)
+// that satisfies the constraints. Every allowed instant is equally likely.
+// The time returned is in the constraints' location.
+// If no time in the range satisfies the constraints, this will panic.
+func SecureRandomTimeConstrained(start, end time.Time, constraints TimeConstraints) time.Time {
+	return randomTimeConstrainedBase(secureInt63n, start, end, constraints)
+}
+
+// PseudoRandomTimeConstrained uses math/rand to return a time between [start, end)
+// that satisfies the constraints. Every allowed instant is equally likely.
+// The time returned is in the constraints' location.
+// If no time in the range satisfies the constraints, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomTimeConstrained(start, end time.Time, constraints TimeConstraints) time.Time {
+	return randomTimeConstrainedBase(rand.Int63n, start, end, constraints)
+}
+
+// PseudoRandomTimeConstrainedRand uses math/rand to return a time between [start, end)
+// that satisfies the constraints. Every allowed instant is equally likely.
+// The time returned is in the constraints' location.
+// If no time in the range satisfies the constraints, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomTimeConstrainedRand(rand *rand.Rand, start, end time.Time, constraints TimeConstraints) time.Time {
+	return randomTimeConstrainedBase(rand.Int63n, start, end, constraints)
+}
+
+// SecureRandomDate uses crypto/rand to return midnight of a random calendar day,
+// in the constraints' location, whose day overlaps [start, end) and is an allowed weekday.
+// Every allowed day is equally likely. The constraints' time of day window is ignored.
+// If no day in the range is allowed, this will panic.
+func SecureRandomDate(start, end time.Time, constraints TimeConstraints) time.Time {
+	return randomDateBase(secureInt63n, start, end, constraints)
+}
+
+// PseudoRandomDate uses math/rand to return midnight of a random calendar day,
+// in the constraints' location, whose day overlaps [start, end) and is an allowed weekday.
+// Every allowed day is equally likely. The constraints' time of day window is ignored.
+// If no day in the range is allowed, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomDate(start, end time.Time, constraints TimeConstraints) time.Time {
+	return randomDateBase(rand.Int63n, start, end, constraints)
+}
+
+// PseudoRandomDateRand uses math/rand to return midnight of a random calendar day,
+// in the constraints' location, whose day overlaps [start, end) and is an allowed weekday.
+// Every allowed day is equally likely. The constraints' time of day window is ignored.
+// If no day in the range is allowed, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomDateRand(rand *rand.Rand, start, end time.Time, constraints TimeConstraints) time.Time {
+	return randomDateBase(rand.Int63n, start, end, constraints)
+}
+
+// secureInt63n uses crypto/rand to return a number between [0, n).
+func secureInt63n(n int64) int64 {
+	return SecureRandomNumber(0, n)
+}
+
+// timeWindow is an allowed interval of time, [start, end).
+type timeWindow struct {
+	start, end time.Time
+}
+
+// randomTimeConstrainedBase returns a time between [start, end) that satisfies the constraints.
+func randomTimeConstrainedBase(int63n func(int64) int64, start, end time.Time, constraints TimeConstraints) time.Time {
+	windows := constrainedWindows(start, end, constraints, false)
+
+	// cumulative is the total duration of each window plus all windows before it
+	cumulative := make([]time.Duration, len(windows))
+	var total time.Duration
+	for i, w := range windows {
+		total += w.end.Sub(w.start)
+		if total < 0 {
+			panic("random: time range must not overflow time.Duration")
+		}
+		cumulative[i] = total
+	}
+	if total == 0 {
+		panic("random: no time in the range satisfies the constraints")
+	}
+
+	offset := time.Duration(int63n(int64(total)))
+	i := sort.Search(len(cumulative), func(i int) bool { return cumulative[i] > offset })
+	return windows[i].end.Add(offset - cumulative[i])
+}
+
+// randomDateBase returns midnight of an allowed day overlapping [start, end).
+func randomDateBase(int63n func(int64) int64, start, end time.Time, constraints TimeConstraints) time.Time {
+	windows := constrainedWindows(start, end, constraints, true)
+	if len(windows) == 0 {
+		panic("random: no day in the range satisfies the constraints")
+	}
+	return windows[int63n(int64(len(windows)))].start
+}
+
+// constrainedWindows returns the allowed windows of time for each day overlapping [start, end).
+// If wholeDays is true, each window is the full day, from midnight to midnight,
+// and is not cut short by start and end.
+func constrainedWindows(start, end time.Time, constraints TimeConstraints, wholeDays bool) []timeWindow {
+	if !end.After(start) {
+		panic("random: end must be after start")
+	}
+
+	loc := constraints.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	startOfDay, endOfDay := constraints.StartOfDay, constraints.EndOfDay
+	if endOfDay == 0 || wholeDays {
+		endOfDay = 24 * time.Hour
+	}
+	if wholeDays {
+		startOfDay = 0
+	}
+	if startOfDay < 0 || endOfDay > 24*time.Hour || startOfDay >= endOfDay {
+		panic("random: StartOfDay must be before EndOfDay, and both must be within a day")
+	}
+
+	var windows []timeWindow
+	y, m, d := start.In(loc).Date()
+	for day := time.Date(y, m, d, 0, 0, 0, 0, loc); day.Before(end); day = time.Date(y, m, d+1, 0, 0, 0, 0, loc) {
+		y, m, d = day.Date()
+		if len(constraints.Weekdays) > 0 && !slices.Contains(constraints.Weekdays, day.Weekday()) {
+			continue
+		}
+
+		// time.Date treats the time of day as wall clock time, so business hours are correct on DST changes
+		w := timeWindow{
+			start: wallClockTime(y, m, d, startOfDay, loc),
+			end:   wallClockTime(y, m, d, endOfDay, loc),
+		}
+		if wholeDays {
+			windows = append(windows, w)
+			continue
+		}
+		if w.start.Before(start) {
+			w.start = start.In(loc)
+		}
+		if w.end.After(end) {
+			w.end = end.In(loc)
+		}
+		if w.end.After(w.start) {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+// wallClockTime returns the time on the day when a wall clock shows the time of day.
+// The time of day is split into fields, as its nanoseconds would overflow a 32 bit int.
+func wallClockTime(y int, m time.Month, d int, timeOfDay time.Duration, loc *time.Location) time.Time {
+	hour, minute := int(timeOfDay/time.Hour), int(timeOfDay%time.Hour/time.Minute)
+	sec, nsec := int(timeOfDay%time.Minute/time.Second), int(timeOfDay%time.Second)
+	return time.Date(y, m, d, hour, minute, sec, nsec, loc)
+}
@@ -0,0 +1,118 @@
+package random_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestSecureRandomTime(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 1000; i++ {
+		result := random.SecureRandomTime(start, end)
+		if result.Before(start) || !result.Before(end) {
+			t.Errorf("Expected time in [%s, %s); Got: %s", start, end, result)
+		}
+	}
+}
+
+func TestPseudoRandomTimeRand(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Nanosecond)
+	for i := 0; i < 100; i++ {
+		if result := random.PseudoRandomTimeRand(source, start, end); !result.Equal(start) {
+			t.Errorf("Expected %s; Got: %s", start, result)
+		}
+	}
+}
+
+func TestSecureRandomDuration(t *testing.T) {
+	t.Parallel()
+	for i := 0; i < 1000; i++ {
+		result := random.SecureRandomDuration(-time.Minute, time.Hour)
+		if result < -time.Minute || result >= time.Hour {
+			t.Errorf("Expected duration in [-1m, 1h); Got: %s", result)
+		}
+	}
+}
+
+func TestPseudoRandomDurationRand(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	for i := 0; i < 1000; i++ {
+		result := random.PseudoRandomDurationRand(source, time.Second, time.Minute)
+		if result < time.Second || result >= time.Minute {
+			t.Errorf("Expected duration in [1s, 1m); Got: %s", result)
+		}
+	}
+}
+
+func TestPseudoRandomTimeConstrainedRand(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	constraints := random.TimeConstraints{
+		Location:   loc,
+		Weekdays:   []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		StartOfDay: 9 * time.Hour,
+		EndOfDay:   17 * time.Hour,
+	}
+	// Includes the daylight saving time changes in March and November
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2000; i++ {
+		result := random.PseudoRandomTimeConstrainedRand(source, start, end, constraints)
+		if result.Location() != loc {
+			t.Errorf("Expected location %s; Got: %s", loc, result.Location())
+		}
+		if result.Before(start) || !result.Before(end) {
+			t.Errorf("Expected time in [%s, %s); Got: %s", start, end, result)
+		}
+		if result.Weekday() == time.Saturday || result.Weekday() == time.Sunday {
+			t.Errorf("Expected weekday; Got: %s", result)
+		}
+		if result.Hour() < 9 || result.Hour() >= 17 {
+			t.Errorf("Expected business hours; Got: %s", result)
+		}
+	}
+}
+
+func TestSecureRandomDate(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 2, 1, 15, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	constraints := random.TimeConstraints{Weekdays: []time.Weekday{time.Saturday}}
+	seen := map[int]bool{}
+	for i := 0; i < 1000; i++ {
+		result := random.SecureRandomDate(start, end, constraints)
+		if result.Weekday() != time.Saturday || result.Month() != time.February || result.Hour() != 0 {
+			t.Errorf("Expected midnight of a Saturday in February; Got: %s", result)
+		}
+		seen[result.Day()] = true
+	}
+	// February 2024 has Saturdays on the 3rd, 10th, 17th and 24th
+	if len(seen) != 4 {
+		t.Errorf("Expected 4 distinct Saturdays; Got: %v", seen)
+	}
+}
+
+func TestPseudoRandomTimeConstrainedNone(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic when no time satisfies the constraints")
+		}
+	}()
+	// January 1st 2024 is a Monday
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	random.PseudoRandomTimeConstrained(start, start.Add(24*time.Hour), random.TimeConstraints{Weekdays: []time.Weekday{time.Sunday}})
+}
@@ -0,0 +1,500 @@
+package random
+
+import (
+	"math/bits"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// RuneExclusion is a set of flags for code points to leave out of RuneRanges.
+type RuneExclusion uint8
+
+const (
+	// ExcludeUnassigned leaves out code points that are not assigned a character
+	// in the version of Unicode supported by the unicode package.
+	ExcludeUnassigned RuneExclusion = 1 << iota
+
+	// ExcludeControl leaves out the C0 and C1 control characters (category Cc).
+	ExcludeControl
+
+	// ExcludeSurrogates leaves out the surrogate code points U+D800 to U+DFFF (category Cs).
+	// Surrogates can not be encoded in UTF-8, so if they are not excluded they will
+	// be written as utf8.RuneError (U+FFFD).
+	ExcludeSurrogates
+
+	// ExcludePrivateUse leaves out the private use areas (category Co).
+	ExcludePrivateUse
+)
+
+// RuneRanges is a set of code points that random strings can be made from,
+// without materialising a []rune of every available character.
+// A RuneRanges is safe for concurrent use.
+type RuneRanges struct {
+	// ranges are sorted, non-overlapping, and non-adjacent inclusive ranges of code points
+	ranges []runeRange
+
+	// cumulative is the number of code points in each range plus all ranges before it
+	cumulative []uint32
+}
+
+// runeRange is an inclusive range of code points
+type runeRange struct {
+	lo, hi rune
+}
+
+// NewRuneRanges returns the union of the code points in the tables, minus any exclusions.
+// For example, NewRuneRanges(ExcludeUnassigned, unicode.Han) or
+// NewRuneRanges(ExcludeUnassigned|ExcludeControl|ExcludeSurrogates, CodePointRange(0, unicode.MaxRune)).
+// If no code points remain, this will panic.
+func NewRuneRanges(exclude RuneExclusion, tables ...*unicode.RangeTable) *RuneRanges {
+	var ranges []runeRange
+	for _, table := range tables {
+		ranges = append(ranges, rangeTableRanges(table)...)
+	}
+	ranges = mergeRuneRanges(ranges)
+
+	if exclude&ExcludeUnassigned != 0 {
+		// Only the two letter categories are used, as newer versions of the unicode package
+		// include unassigned code points in the Cn category and therefore in C
+		var assigned []runeRange
+		for name, table := range unicode.Categories {
+			if len(name) == 2 && name != "Cn" {
+				assigned = append(assigned, rangeTableRanges(table)...)
+			}
+		}
+		ranges = intersectRuneRanges(ranges, mergeRuneRanges(assigned))
+	}
+	if exclude&ExcludeControl != 0 {
+		ranges = subtractRuneRanges(ranges, rangeTableRanges(unicode.Cc))
+	}
+	if exclude&ExcludeSurrogates != 0 {
+		ranges = subtractRuneRanges(ranges, rangeTableRanges(unicode.Cs))
+	}
+	if exclude&ExcludePrivateUse != 0 {
+		ranges = subtractRuneRanges(ranges, rangeTableRanges(unicode.Co))
+	}
+
+	return newRuneRangesMerged(ranges)
+}
+
+// newRuneRangesMerged returns a RuneRanges for ranges that have already been merged.
+// If there are no ranges, this will panic.
+func newRuneRangesMerged(ranges []runeRange) *RuneRanges {
+	if len(ranges) == 0 {
+		panic("random: RuneRanges must not be empty")
+	}
+	rr := &RuneRanges{ranges: ranges, cumulative: make([]uint32, len(ranges))}
+	var total uint32
+	for i, r := range ranges {
+		total += uint32(r.hi-r.lo) + 1
+		rr.cumulative[i] = total
+	}
+	return rr
+}
+
+// CodePointRange returns a table containing the inclusive range of code points
+// from lo to hi, for use with NewRuneRanges.
+func CodePointRange(lo, hi rune) *unicode.RangeTable {
+	if lo < 0 || hi > unicode.MaxRune || lo > hi {
+		panic("random: code point range must be within 0 and unicode.MaxRune, and lo must not be greater than hi")
+	}
+	return &unicode.RangeTable{R32: []unicode.Range32{{Lo: uint32(lo), Hi: uint32(hi), Stride: 1}}}
+}
+
+// Len returns the number of code points in the set.
+func (rr *RuneRanges) Len() int {
+	return int(rr.cumulative[len(rr.cumulative)-1])
+}
+
+// Contains returns true if the code point is in the set.
+func (rr *RuneRanges) Contains(r rune) bool {
+	i := sort.Search(len(rr.ranges), func(i int) bool { return rr.ranges[i].hi >= r })
+	return i < len(rr.ranges) && rr.ranges[i].lo <= r
+}
+
+// rune returns the code point at index idx, where idx is in [0, rr.Len()).
+func (rr *RuneRanges) rune(idx uint32) rune {
+	i := sort.Search(len(rr.cumulative), func(i int) bool { return rr.cumulative[i] > idx })
+	before := uint32(0)
+	if i > 0 {
+		before = rr.cumulative[i-1]
+	}
+	return rr.ranges[i].lo + rune(idx-before)
+}
+
+// Without returns a new RuneRanges containing the code points in this set,
+// minus the code points in the tables.
+// If no code points remain, this will panic.
+func (rr *RuneRanges) Without(tables ...*unicode.RangeTable) *RuneRanges {
+	var excluded []runeRange
+	for _, table := range tables {
+		excluded = append(excluded, rangeTableRanges(table)...)
+	}
+	return newRuneRangesMerged(subtractRuneRanges(rr.ranges, excluded))
+}
+
+// rangeTableRanges converts a unicode.RangeTable into inclusive ranges with a stride of 1.
+func rangeTableRanges(table *unicode.RangeTable) []runeRange {
+	var ranges []runeRange
+	add := func(lo, hi, stride uint32) {
+		if stride == 1 {
+			ranges = append(ranges, runeRange{lo: rune(lo), hi: rune(hi)})
+			return
+		}
+		for r := lo; r <= hi; r += stride {
+			ranges = append(ranges, runeRange{lo: rune(r), hi: rune(r)})
+		}
+	}
+	for _, r := range table.R16 {
+		add(uint32(r.Lo), uint32(r.Hi), uint32(r.Stride))
+	}
+	for _, r := range table.R32 {
+		add(r.Lo, r.Hi, r.Stride)
+	}
+	return ranges
+}
+
+// mergeRuneRanges sorts the ranges and merges any that overlap or are adjacent.
+func mergeRuneRanges(ranges []runeRange) []runeRange {
+	slices.SortFunc(ranges, func(a, b runeRange) int { return int(a.lo - b.lo) })
+	var merged []runeRange
+	for _, r := range ranges {
+		if last := len(merged) - 1; last >= 0 && r.lo <= merged[last].hi+1 {
+			merged[last].hi = max(merged[last].hi, r.hi)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// intersectRuneRanges returns the code points in both a and b, which must be merged.
+func intersectRuneRanges(a, b []runeRange) []runeRange {
+	var result []runeRange
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		lo, hi := max(a[i].lo, b[j].lo), min(a[i].hi, b[j].hi)
+		if lo <= hi {
+			result = append(result, runeRange{lo: lo, hi: hi})
+		}
+		if a[i].hi < b[j].hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return result
+}
+
+// subtractRuneRanges returns the code points in a that are not in b. a must be merged.
+func subtractRuneRanges(a, b []runeRange) []runeRange {
+	b = mergeRuneRanges(b)
+	var complement []runeRange
+	next := rune(0)
+	for _, r := range b {
+		if r.lo > next {
+			complement = append(complement, runeRange{lo: next, hi: r.lo - 1})
+		}
+		next = r.hi + 1
+	}
+	if next <= unicode.MaxRune {
+		complement = append(complement, runeRange{lo: next, hi: unicode.MaxRune})
+	}
+	return intersectRuneRanges(a, complement)
+}
+
+// SecureRandomStringRuneRanges uses crypto/rand to return a random string of given
+// length made from the code points in the RuneRanges, each equally likely.
+// If length is negative, this will panic.
+func SecureRandomStringRuneRanges(length int, availableRuneRanges *RuneRanges) string {
+	return randomStringRuneRangesBase(SecureRandSource.Uint64, length, availableRuneRanges)
+}
+
+// PseudoRandomStringRuneRanges uses math/rand to return a random string of given
+// length made from the code points in the RuneRanges, each equally likely.
+// If length is negative, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomStringRuneRanges(length int, availableRuneRanges *RuneRanges) string {
+	return randomStringRuneRangesBase(rand.Uint64, length, availableRuneRanges)
+}
+
+// PseudoRandomStringRuneRangesRand uses math/rand to return a random string of given
+// length made from the code points in the RuneRanges, each equally likely.
+// If length is negative, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomStringRuneRangesRand(rand *rand.Rand, length int, availableRuneRanges *RuneRanges) string {
+	return randomStringRuneRangesBase(rand.Uint64, length, availableRuneRanges)
+}
+
+// randomStringRuneRangesBase returns a random string of given length made from
+// the code points in the RuneRanges, each equally likely.
+// If length is negative, this will panic.
+func randomStringRuneRangesBase(randUint64 func() uint64, length int, availableRuneRanges *RuneRanges) string {
+
+	// Check length
+	if length < 0 {
+		panic("random: length can not be negative")
+	}
+
+	// The resulting string
+	result := make([]rune, length)
+	indexer := newRandomIndexer(randUint64, uint64(availableRuneRanges.Len()))
+	for i := range result {
+		result[i] = availableRuneRanges.rune(uint32(indexer.next()))
+	}
+	return string(result)
+}
+
+// randomIndexer returns uniformly random indices in [0, n), using as few bits
+// of random data as possible, in the same way as pseudoRandomStringBytesBase.
+type randomIndexer struct {
+	randUint64 func() uint64
+	n          uint64
+
+	// bitsNeeded is how many bits are needed to represent all indices
+	bitsNeeded uint
+
+	// bitMask is a mask (ie: 11111) with bitsNeeded bits
+	bitMask uint64
+
+	// randomBits holds the random bits not yet used, and remaining is how many there are
+	randomBits uint64
+	remaining  uint
+}
+
+// newRandomIndexer returns a randomIndexer for indices in [0, n). If n is zero, this will panic.
+func newRandomIndexer(randUint64 func() uint64, n uint64) *randomIndexer {
+	ri := &randomIndexer{randUint64: randUint64}
+	ri.setN(n)
+	return ri
+}
+
+// setN changes the indices to [0, n), keeping the random bits not yet used,
+// so that indices with a different n each time do not need a new randomIndexer.
+// If n is zero, this will panic.
+func (ri *randomIndexer) setN(n uint64) {
+	if n == 0 {
+		panic("random: n must be greater than zero")
+	}
+	ri.n = n
+	ri.bitsNeeded = uint(bits.Len64(n - 1))
+	ri.bitMask = 1<<ri.bitsNeeded - 1
+}
+
+// next returns the next random index.
+func (ri *randomIndexer) next() uint64 {
+	if ri.bitsNeeded == 0 {
+		return 0
+	}
+	for {
+		if ri.remaining < ri.bitsNeeded {
+			ri.randomBits = ri.randUint64()
+			ri.remaining = 64
+		}
+
+		// Mask bits to get an index
+		idx := ri.randomBits & ri.bitMask
+
+		// Right shift to get rid of bits used
+		ri.randomBits >>= ri.bitsNeeded
+		ri.remaining -= ri.bitsNeeded
+
+		// If the index is not within n, we must ignore it in order to maintain equal probability and distribution.
+		if idx < ri.n {
+			return idx
+		}
+	}
+}
+
+// graphemeSets are the grapheme cluster building blocks used by the random grapheme functions.
+// Every base and emoji must start a new cluster, and every mark must not join the cluster
+// to the next one, so that each generated cluster is exactly one cluster.
+type graphemeSets struct {
+	bases, marks, emoji, skinTones, regionalIndicators, hangulSyllables *RuneRanges
+}
+
+var (
+	// graphemeSkinTones are the emoji modifiers, which extend the emoji before them
+	graphemeSkinTones = CodePointRange(0x1F3FB, 0x1F3FF)
+
+	// graphemeRegionalIndicators pair up with each other to make flags
+	graphemeRegionalIndicators = CodePointRange(0x1F1E6, 0x1F1FF)
+
+	// graphemeNonPictographic are symbols within the emoji blocks that are not Extended_Pictographic,
+	// so can not be joined into emoji ZWJ sequences (Unicode 17 emoji-data.txt)
+	graphemeNonPictographic = &unicode.RangeTable{
+		R32: []unicode.Range32{
+			{Lo: 0x1F53E, Hi: 0x1F545, Stride: 1},
+			{Lo: 0x1F900, Hi: 0x1F90B, Stride: 1},
+			{Lo: 0x1F93B, Hi: 0x1F946, Stride: 0x1F946 - 0x1F93B},
+		},
+	}
+
+	// graphemeJoiningLetters are letters whose Grapheme_Cluster_Break property joins them to a
+	// neighboring cluster: SpacingMark ones, which join the cluster before them, and Prepend ones,
+	// which join the cluster after them (Unicode 17 GraphemeBreakProperty.txt)
+	graphemeJoiningLetters = &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{Lo: 0x0D4E, Hi: 0x0D4E, Stride: 1}, // Prepend
+			{Lo: 0x0E33, Hi: 0x0E33, Stride: 1}, // SpacingMark
+			{Lo: 0x0EB3, Hi: 0x0EB3, Stride: 1}, // SpacingMark
+		},
+		R32: []unicode.Range32{
+			{Lo: 0x111C2, Hi: 0x111C3, Stride: 1}, // Prepend
+			{Lo: 0x113D1, Hi: 0x113D1, Stride: 1}, // Prepend
+			{Lo: 0x1193F, Hi: 0x11941, Stride: 2}, // Prepend
+			{Lo: 0x11A84, Hi: 0x11A89, Stride: 1}, // Prepend
+			{Lo: 0x11D46, Hi: 0x11D46, Stride: 1}, // Prepend
+			{Lo: 0x11F02, Hi: 0x11F02, Stride: 1}, // Prepend
+		},
+	}
+
+	// graphemeLinkers are the viramas with Indic_Conjunct_Break=Linker, which join a consonant
+	// to the consonant that starts the next cluster (Unicode 17 DerivedCoreProperties.txt)
+	graphemeLinkers = &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{Lo: 0x094D, Hi: 0x094D, Stride: 1},
+			{Lo: 0x09CD, Hi: 0x09CD, Stride: 1},
+			{Lo: 0x0ACD, Hi: 0x0ACD, Stride: 1},
+			{Lo: 0x0B4D, Hi: 0x0B4D, Stride: 1},
+			{Lo: 0x0C4D, Hi: 0x0C4D, Stride: 1},
+			{Lo: 0x0D4D, Hi: 0x0D4D, Stride: 1},
+			{Lo: 0x1039, Hi: 0x1039, Stride: 1},
+			{Lo: 0x17D2, Hi: 0x17D2, Stride: 1},
+			{Lo: 0x1A60, Hi: 0x1A60, Stride: 1},
+			{Lo: 0x1BAB, Hi: 0x1BAB, Stride: 1},
+			{Lo: 0xAAF6, Hi: 0xAAF6, Stride: 1},
+		},
+		R32: []unicode.Range32{
+			{Lo: 0x10A3F, Hi: 0x10A3F, Stride: 1},
+			{Lo: 0x11133, Hi: 0x11133, Stride: 1},
+			{Lo: 0x113D0, Hi: 0x113D0, Stride: 1},
+			{Lo: 0x1193E, Hi: 0x1193E, Stride: 1},
+			{Lo: 0x11A47, Hi: 0x11A47, Stride: 1},
+			{Lo: 0x11A99, Hi: 0x11A99, Stride: 1},
+			{Lo: 0x11F42, Hi: 0x11F42, Stride: 1},
+		},
+	}
+)
+
+// graphemeTables are built when first used, so that importing this package does not build them.
+var graphemeTables = sync.OnceValue(func() *graphemeSets {
+	return &graphemeSets{
+		// Hangul jamo and regional indicators are left out of the bases, as they could join with the
+		// next cluster, as are the letters and symbols that extend or join a neighboring cluster
+		bases: NewRuneRanges(ExcludeUnassigned, unicode.L, unicode.N, unicode.P, unicode.S).
+			Without(unicode.Hangul, graphemeRegionalIndicators, graphemeSkinTones, unicode.Other_Grapheme_Extend, graphemeJoiningLetters),
+		marks: NewRuneRanges(ExcludeUnassigned, unicode.Mn, unicode.Me).Without(graphemeLinkers),
+		emoji: NewRuneRanges(ExcludeUnassigned,
+			CodePointRange(0x1F300, 0x1F5FF), // Miscellaneous Symbols and Pictographs
+			CodePointRange(0x1F600, 0x1F64F), // Emoticons
+			CodePointRange(0x1F680, 0x1F6FF), // Transport and Map Symbols
+			CodePointRange(0x1F900, 0x1F9FF), // Supplemental Symbols and Pictographs
+		).Without(graphemeSkinTones, graphemeNonPictographic),
+		skinTones:          NewRuneRanges(0, graphemeSkinTones),
+		regionalIndicators: NewRuneRanges(0, graphemeRegionalIndicators),
+		hangulSyllables:    NewRuneRanges(0, CodePointRange(0xAC00, 0xD7A3)),
+	}
+})
+
+const (
+	zeroWidthJoiner      = '\u200D'
+	emojiPresentation    = '\uFE0F'
+	graphemeClusterKinds = 6
+)
+
+// SecureRandomGraphemes uses crypto/rand to return a string made of the given number
+// of random grapheme clusters, intended for fuzzing text processing code.
+// Clusters include letters and symbols with stacked combining marks, emoji with skin
+// tone modifiers and presentation selectors, emoji ZWJ sequences, regional indicator
+// flag pairs, Hangul syllables, and CRLF.
+// If count is negative, this will panic.
+func SecureRandomGraphemes(count int) string {
+	return randomGraphemesBase(SecureRandSource.Uint64, count)
+}
+
+// PseudoRandomGraphemes uses math/rand to return a string made of the given number
+// of random grapheme clusters, intended for fuzzing text processing code.
+// See SecureRandomGraphemes for the kinds of clusters generated.
+// If count is negative, this will panic.
+// Uses the global math/rand instance, which locks on each call.
+// Not cryptographically secure.
+func PseudoRandomGraphemes(count int) string {
+	return randomGraphemesBase(rand.Uint64, count)
+}
+
+// PseudoRandomGraphemesRand uses math/rand to return a string made of the given number
+// of random grapheme clusters, intended for fuzzing text processing code.
+// See SecureRandomGraphemes for the kinds of clusters generated.
+// If count is negative, this will panic.
+// Allows passing in rand source to avoid locking or to use other RNG's.
+// Not cryptographically secure.
+func PseudoRandomGraphemesRand(rand *rand.Rand, count int) string {
+	return randomGraphemesBase(rand.Uint64, count)
+}
+
+// randomGraphemesBase returns a string made of count random grapheme clusters.
+func randomGraphemesBase(randUint64 func() uint64, count int) string {
+
+	// Check count
+	if count < 0 {
+		panic("random: count can not be negative")
+	}
+
+	// Each set of runes gets its own indexer, so that unused random bits are not thrown away
+	indexers := map[*RuneRanges]*randomIndexer{}
+	pick := func(rr *RuneRanges) rune {
+		indexer, ok := indexers[rr]
+		if !ok {
+			indexer = newRandomIndexer(randUint64, uint64(rr.Len()))
+			indexers[rr] = indexer
+		}
+		return rr.rune(uint32(indexer.next()))
+	}
+	sets := graphemeTables()
+	small := newRandomIndexer(randUint64, 4)
+	kinds := newRandomIndexer(randUint64, graphemeClusterKinds)
+
+	var sb strings.Builder
+	for i := 0; i < count; i++ {
+		switch kinds.next() {
+		case 0: // Base character with zero to three combining marks
+			sb.WriteRune(pick(sets.bases))
+			for marks := small.next(); marks > 0; marks-- {
+				sb.WriteRune(pick(sets.marks))
+			}
+
+		case 1: // Emoji, optionally with a skin tone modifier or presentation selector
+			sb.WriteRune(pick(sets.emoji))
+			switch small.next() {
+			case 1:
+				sb.WriteRune(pick(sets.skinTones))
+			case 2:
+				sb.WriteRune(emojiPresentation)
+			}
+
+		case 2: // Emoji ZWJ sequence of two to five emoji
+			sb.WriteRune(pick(sets.emoji))
+			for joined := small.next() + 1; joined > 0; joined-- {
+				sb.WriteRune(zeroWidthJoiner)
+				sb.WriteRune(pick(sets.emoji))
+			}
+
+		case 3: // Regional indicator pair (flag)
+			sb.WriteRune(pick(sets.regionalIndicators))
+			sb.WriteRune(pick(sets.regionalIndicators))
+
+		case 4: // Precomposed Hangul syllable
+			sb.WriteRune(pick(sets.hangulSyllables))
+
+		case 5: // CRLF is a single grapheme cluster
+			sb.WriteString("\r\n")
+		}
+	}
+	return sb.String()
+}
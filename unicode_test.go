@@ -0,0 +1,259 @@
+package random_test
+
+import (
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/veqryn/go-random"
+	"github.com/veqryn/go-random/randtest"
+)
+
+func TestNewRuneRanges(t *testing.T) {
+	t.Parallel()
+	if l := random.NewRuneRanges(0, random.CodePointRange('a', 'z'), random.CodePointRange('m', 'z'), random.CodePointRange('0', '9')).Len(); l != 36 {
+		t.Errorf("Expecting 36 code points; Got: %d", l)
+	}
+	all := random.NewRuneRanges(excludeAll, random.CodePointRange(0, unicode.MaxRune))
+	for _, r := range []rune{0, '\n', 0x7F, 0x85, 0xD800, 0xDFFF, 0xE000, 0x0378} {
+		if all.Contains(r) {
+			t.Errorf("Expecting %U to be excluded", r)
+		}
+	}
+	for _, r := range []rune{'a', ' ', 'é', '世', 0x1F600} {
+		if !all.Contains(r) {
+			t.Errorf("Expecting %U to be included", r)
+		}
+	}
+	if l := random.NewRuneRanges(0, random.CodePointRange('a', 'z')).Without(random.CodePointRange('b', 'y')).Len(); l != 2 {
+		t.Errorf("Expecting 2 code points; Got: %d", l)
+	}
+}
+
+const excludeAll = random.ExcludeUnassigned | random.ExcludeControl | random.ExcludeSurrogates | random.ExcludePrivateUse
+
+func TestSecureRandomStringRuneRanges(t *testing.T) {
+	t.Parallel()
+	han := random.NewRuneRanges(random.ExcludeUnassigned, unicode.Han)
+	for length := 0; length <= 128; length++ {
+		result := random.SecureRandomStringRuneRanges(length, han)
+		if utf8.RuneCountInString(result) != length {
+			t.Errorf("Expecting length %d; Got: %d", length, utf8.RuneCountInString(result))
+		}
+		for _, r := range result {
+			if !unicode.Is(unicode.Han, r) {
+				t.Errorf("Expecting only Han characters; Got: %U", r)
+			}
+		}
+	}
+}
+
+func TestPseudoRandomStringRuneRangesRand(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	rr := random.NewRuneRanges(0, random.CodePointRange('a', 'c'), random.CodePointRange(0x10000, 0x10001))
+	counts := map[rune]int{}
+	for _, r := range random.PseudoRandomStringRuneRangesRand(source, 10000, rr) {
+		counts[r]++
+	}
+	if len(counts) != 5 {
+		t.Errorf("Expecting 5 distinct runes; Got: %v", counts)
+	}
+	for r, count := range counts {
+		if count < 1700 || count > 2300 {
+			t.Errorf("Expecting roughly 2000 of %U; Got: %d", r, count)
+		}
+	}
+
+	all := random.NewRuneRanges(excludeAll, random.CodePointRange(0, unicode.MaxRune))
+	result := random.PseudoRandomStringRuneRangesRand(source, 10000, all)
+	if !utf8.ValidString(result) {
+		t.Errorf("Expecting valid utf8; Got: %q", result)
+	}
+	// U+FFFD is an assigned code point, so it is not checked for, even though it is also utf8.RuneError
+	for _, r := range result {
+		if unicode.IsControl(r) || unicode.In(r, unicode.Co) {
+			t.Errorf("Expecting no excluded code points; Got: %U", r)
+		}
+	}
+}
+
+func TestPseudoRandomGraphemesRand(t *testing.T) {
+	t.Parallel()
+	source := randtest.Rand(t)
+	for count := 0; count <= 128; count++ {
+		result := random.PseudoRandomGraphemesRand(source, count)
+		if !utf8.ValidString(result) {
+			t.Errorf("Expecting valid UTF-8; Got: %q", result)
+		}
+		if clusters := countGraphemes(result); clusters != count {
+			t.Errorf("Expecting %d grapheme clusters; Got: %d in %+q", count, clusters, result)
+		}
+	}
+	if random.SecureRandomGraphemes(10) == "" {
+		t.Error("Expecting non-empty graphemes")
+	}
+}
+
+// graphemeBreak is the Grapheme_Cluster_Break property of UAX #29.
+type graphemeBreak int
+
+const (
+	gbOther graphemeBreak = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+)
+
+var (
+	// gbPrependTable is Grapheme_Cluster_Break=Prepend (Unicode 17 GraphemeBreakProperty.txt)
+	gbPrependTable = &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{Lo: 0x0600, Hi: 0x0605, Stride: 1},
+			{Lo: 0x06DD, Hi: 0x070F, Stride: 0x070F - 0x06DD},
+			{Lo: 0x0890, Hi: 0x0891, Stride: 1},
+			{Lo: 0x08E2, Hi: 0x0D4E, Stride: 0x0D4E - 0x08E2},
+		},
+		R32: []unicode.Range32{
+			{Lo: 0x110BD, Hi: 0x110CD, Stride: 0x10},
+			{Lo: 0x111C2, Hi: 0x111C3, Stride: 1},
+			{Lo: 0x113D1, Hi: 0x113D1, Stride: 1},
+			{Lo: 0x1193F, Hi: 0x11941, Stride: 2},
+			{Lo: 0x11A84, Hi: 0x11A89, Stride: 1},
+			{Lo: 0x11D46, Hi: 0x11F02, Stride: 0x11F02 - 0x11D46},
+		},
+	}
+
+	// gbLinkerTable is Indic_Conjunct_Break=Linker (Unicode 17 DerivedCoreProperties.txt)
+	gbLinkerTable = &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{Lo: 0x094D, Hi: 0x09CD, Stride: 0x80},
+			{Lo: 0x0ACD, Hi: 0x0B4D, Stride: 0x80},
+			{Lo: 0x0C4D, Hi: 0x0D4D, Stride: 0x100},
+			{Lo: 0x1039, Hi: 0x1039, Stride: 1},
+			{Lo: 0x17D2, Hi: 0x17D2, Stride: 1},
+			{Lo: 0x1A60, Hi: 0x1A60, Stride: 1},
+			{Lo: 0x1BAB, Hi: 0x1BAB, Stride: 1},
+			{Lo: 0xAAF6, Hi: 0xAAF6, Stride: 1},
+		},
+		R32: []unicode.Range32{
+			{Lo: 0x10A3F, Hi: 0x10A3F, Stride: 1},
+			{Lo: 0x11133, Hi: 0x11133, Stride: 1},
+			{Lo: 0x113D0, Hi: 0x113D0, Stride: 1},
+			{Lo: 0x1193E, Hi: 0x1193E, Stride: 1},
+			{Lo: 0x11A47, Hi: 0x11A99, Stride: 0x11A99 - 0x11A47},
+			{Lo: 0x11F42, Hi: 0x11F42, Stride: 1},
+		},
+	}
+
+	// gbPictographicTable is Extended_Pictographic, for the planes the emoji are drawn from (Unicode 17 emoji-data.txt)
+	gbPictographicTable = &unicode.RangeTable{
+		R32: []unicode.Range32{
+			{Lo: 0x1F249, Hi: 0x1F3FA, Stride: 1},
+			{Lo: 0x1F400, Hi: 0x1F53D, Stride: 1},
+			{Lo: 0x1F546, Hi: 0x1F64F, Stride: 1},
+			{Lo: 0x1F680, Hi: 0x1F6FF, Stride: 1},
+			{Lo: 0x1F90C, Hi: 0x1F93A, Stride: 1},
+			{Lo: 0x1F93C, Hi: 0x1F945, Stride: 1},
+			{Lo: 0x1F947, Hi: 0x1FAFF, Stride: 1},
+		},
+	}
+)
+
+// graphemeBreakOf returns the Grapheme_Cluster_Break property of the rune.
+func graphemeBreakOf(r rune) graphemeBreak {
+	switch {
+	case r == '\r':
+		return gbCR
+	case r == '\n':
+		return gbLF
+	case r == '\u200D':
+		return gbZWJ
+	case unicode.In(r, gbPrependTable):
+		return gbPrepend
+	case r == '\u200C', r >= 0x1F3FB && r <= 0x1F3FF, unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend):
+		return gbExtend
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gbControl
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gbRegionalIndicator
+	case r == 0x0E33, r == 0x0EB3, unicode.In(r, unicode.Mc):
+		return gbSpacingMark
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gbL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gbV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gbT
+	case r >= 0xAC00 && r <= 0xD7A3 && (r-0xAC00)%28 == 0:
+		return gbLV
+	case r >= 0xAC00 && r <= 0xD7A3:
+		return gbLVT
+	}
+	return gbOther
+}
+
+// countGraphemes counts the extended grapheme clusters of the string, following the UAX #29 rules.
+// Indic conjuncts (GB9c) are approximated as any letter following a linker.
+func countGraphemes(s string) int {
+	count := 0
+	var prev graphemeBreak
+	var pictographic, pictographicZWJ, linked bool
+	regionalIndicators := 0
+	for i, r := range []rune(s) {
+		cur := graphemeBreakOf(r)
+		joined := false
+		if i > 0 {
+			switch {
+			case prev == gbCR && cur == gbLF: // GB3
+				joined = true
+			case prev == gbCR || prev == gbLF || prev == gbControl || cur == gbCR || cur == gbLF || cur == gbControl: // GB4, GB5
+			case prev == gbL && (cur == gbL || cur == gbV || cur == gbLV || cur == gbLVT): // GB6
+				joined = true
+			case (prev == gbLV || prev == gbV) && (cur == gbV || cur == gbT): // GB7
+				joined = true
+			case (prev == gbLVT || prev == gbT) && cur == gbT: // GB8
+				joined = true
+			case cur == gbExtend || cur == gbZWJ || cur == gbSpacingMark || prev == gbPrepend: // GB9, GB9a, GB9b
+				joined = true
+			case linked && unicode.IsLetter(r): // GB9c
+				joined = true
+			case prev == gbZWJ && pictographicZWJ && unicode.In(r, gbPictographicTable): // GB11
+				joined = true
+			case prev == gbRegionalIndicator && cur == gbRegionalIndicator && regionalIndicators%2 == 1: // GB12, GB13
+				joined = true
+			}
+		}
+		if !joined {
+			count++
+		}
+
+		// Track the state the later rules need
+		pictographicZWJ = cur == gbZWJ && pictographic
+		if cur != gbExtend {
+			pictographic = unicode.In(r, gbPictographicTable)
+		}
+		if unicode.In(r, gbLinkerTable) {
+			linked = true
+		} else if cur != gbExtend && cur != gbZWJ {
+			linked = false
+		}
+		if cur == gbRegionalIndicator {
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+		prev = cur
+	}
+	return count
+}
@@ -0,0 +1,184 @@
+package random
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// XID is a globally unique ID in the format of MongoDB's ObjectId: a 32 bit timestamp in seconds,
+// a 3 byte machine ID, a 2 byte process ID, and a 3 byte counter that starts at a random value.
+// Its string form is 20 lower case base32hex characters, and sorts in the same order as the timestamps, to the second.
+// It is compatible with github.com/rs/xid.
+type XID [12]byte
+
+// ErrInvalidXID is returned when parsing or scanning a value that is not an XID.
+var ErrInvalidXID = errors.New("random: invalid XID")
+
+// xidEncoding is lower case base32hex without padding, which sorts in ASCII order.
+var xidEncoding = base32.NewEncoding("0123456789abcdefghijklmnopqrstuv").WithPadding(base32.NoPadding)
+
+// xidStringLength is the length of the base32hex string form.
+const xidStringLength = 20
+
+// xidState is the machine, process and counter shared by every XID from this process.
+type xidState struct {
+	machine [3]byte
+	pid     uint16
+	counter atomic.Uint32
+}
+
+// xidProcess is the xidState of this process. It is created when first used,
+// so that importing this package does not read the hostname or crypto/rand.
+var xidProcess = sync.OnceValue(func() *xidState {
+	state := &xidState{pid: uint16(os.Getpid())}
+
+	// The machine is identified by a hash of its hostname, or randomly if it has none
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		sum := sha256.Sum256([]byte(hostname))
+		copy(state.machine[:], sum[:])
+	} else {
+		secureFill(state.machine[:])
+	}
+
+	// The counter starts at a random value, so that IDs from a restarted process are unlikely to repeat
+	var b [4]byte
+	secureFill(b[1:])
+	state.counter.Store(binary.BigEndian.Uint32(b[:]))
+	return state
+})
+
+// NewXID returns a new XID for the current time.
+func NewXID() XID {
+	return NewXIDWithTime(time.Now())
+}
+
+// NewXIDWithTime returns a new XID for the given time, which is truncated to the second.
+// If the time is outside of the range XIDs can represent (1970 to 2106), this will panic.
+func NewXIDWithTime(t time.Time) XID {
+	seconds := t.Unix()
+	if seconds < 0 || seconds > 1<<32-1 {
+		panic("random: time is outside of the range of XID")
+	}
+	var x XID
+	binary.BigEndian.PutUint32(x[:4], uint32(seconds))
+	process := xidProcess()
+	copy(x[4:7], process.machine[:])
+	binary.BigEndian.PutUint16(x[7:9], process.pid)
+	counter := process.counter.Add(1)
+	x[9], x[10], x[11] = byte(counter>>16), byte(counter>>8), byte(counter)
+	return x
+}
+
+// ParseXID parses the 20 character base32hex string form of an XID.
+func ParseXID(s string) (XID, error) {
+	var x XID
+	if len(s) != xidStringLength {
+		return x, ErrInvalidXID
+	}
+	n, err := xidEncoding.Decode(x[:], []byte(s))
+	// The last character only holds 1 bit, so its other bits must be zero for the string to be canonical
+	if err != nil || n != len(x) || xidEncoding.EncodeToString(x[:]) != s {
+		return XID{}, ErrInvalidXID
+	}
+	return x, nil
+}
+
+// String returns the 20 character base32hex form of the XID.
+func (x XID) String() string {
+	return xidEncoding.EncodeToString(x[:])
+}
+
+// Time returns the time the XID was created, to the second.
+func (x XID) Time() time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(x[:4])), 0)
+}
+
+// Machine returns the 3 byte machine ID of the XID.
+func (x XID) Machine() []byte {
+	return bytes.Clone(x[4:7])
+}
+
+// Pid returns the process ID of the XID.
+func (x XID) Pid() uint16 {
+	return binary.BigEndian.Uint16(x[7:9])
+}
+
+// Counter returns the counter of the XID.
+func (x XID) Counter() uint32 {
+	return uint32(x[9])<<16 | uint32(x[10])<<8 | uint32(x[11])
+}
+
+// IsNil returns true if the XID is all zeros.
+func (x XID) IsNil() bool {
+	return x == XID{}
+}
+
+// Compare returns -1, 0 or 1 if x sorts before, the same as, or after other.
+// XIDs sort by time, then by machine, process and counter.
+func (x XID) Compare(other XID) int {
+	return bytes.Compare(x[:], other[:])
+}
+
+// MarshalText implements encoding.TextMarshaler, which is also used for JSON.
+func (x XID) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, which is also used for JSON.
+func (x *XID) UnmarshalText(text []byte) error {
+	parsed, err := ParseXID(string(text))
+	if err != nil {
+		return err
+	}
+	*x = parsed
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (x XID) MarshalBinary() ([]byte, error) {
+	return bytes.Clone(x[:]), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (x *XID) UnmarshalBinary(data []byte) error {
+	if len(data) != len(x) {
+		return ErrInvalidXID
+	}
+	copy(x[:], data)
+	return nil
+}
+
+// Value implements database/sql/driver.Valuer, storing the XID as its string form.
+// The nil XID is stored as NULL.
+func (x XID) Value() (driver.Value, error) {
+	if x.IsNil() {
+		return nil, nil
+	}
+	return x.String(), nil
+}
+
+// Scan implements database/sql.Scanner, accepting the string form, the 12 raw bytes, or NULL.
+func (x *XID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*x = XID{}
+		return nil
+	case string:
+		return x.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(x) {
+			return x.UnmarshalBinary(v)
+		}
+		return x.UnmarshalText(v)
+	}
+	return fmt.Errorf("random: can not scan %T into XID", src)
+}
@@ -0,0 +1,76 @@
+package random_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/veqryn/go-random"
+)
+
+// Example from github.com/rs/xid
+func TestParseXID(t *testing.T) {
+	t.Parallel()
+	x, err := random.ParseXID("9m4e2mr0ui3e8a215n4g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := random.XID{0x4d, 0x88, 0xe1, 0x5b, 0x60, 0xf4, 0x86, 0xe4, 0x28, 0x41, 0x2d, 0xc9}
+	if x != expected {
+		t.Errorf("Expecting %x; Got: %x", expected, x)
+	}
+	if x.Time().Unix() != 1300816219 || !bytes.Equal(x.Machine(), []byte{0x60, 0xf4, 0x86}) || x.Pid() != 0xe428 || x.Counter() != 4271561 {
+		t.Errorf("Expecting parts of the XID; Got: %s %x %x %d", x.Time(), x.Machine(), x.Pid(), x.Counter())
+	}
+	if x.String() != "9m4e2mr0ui3e8a215n4g" {
+		t.Errorf("Expecting String to reverse Parse; Got: %s", x.String())
+	}
+	for _, invalid := range []string{"", "9m4e2mr0ui3e8a215n4", "9m4e2mr0ui3e8a215n4G", "9m4e2mr0ui3e8a215n4h", "9m4e2mr0ui3e8a215n4w"} {
+		if _, err := random.ParseXID(invalid); err != random.ErrInvalidXID {
+			t.Errorf("Expecting %q to be invalid; Got: %v", invalid, err)
+		}
+	}
+}
+
+func TestNewXID(t *testing.T) {
+	t.Parallel()
+	a := random.NewXIDWithTime(time.Now().Add(-time.Hour))
+	b := random.NewXID()
+	c := random.NewXID()
+	if a.Compare(b) >= 0 || a.String() >= b.String() {
+		t.Errorf("Expecting earlier XID to sort first; Got: %s and %s", a, b)
+	}
+	if b == c || c.Counter() != (b.Counter()+1)&0xffffff || !bytes.Equal(b.Machine(), c.Machine()) || b.Pid() != c.Pid() {
+		t.Errorf("Expecting XIDs to only differ by counter; Got: %s and %s", b, c)
+	}
+
+	var decoded struct{ ID random.XID }
+	data, _ := json.Marshal(struct{ ID random.XID }{b})
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID != b {
+		t.Errorf("Expecting JSON round trip; Got: %s %v", data, err)
+	}
+
+	var scanned random.XID
+	value, _ := b.Value()
+	if err := scanned.Scan(value); err != nil || scanned != b {
+		t.Errorf("Expecting SQL round trip; Got: %s %v", scanned, err)
+	}
+	if err := scanned.Scan(42); err == nil {
+		t.Error("Expecting error scanning an int")
+	}
+}
+
+func TestNewXIDWithTimeOutOfRange(t *testing.T) {
+	t.Parallel()
+	for _, tm := range []time.Time{time.Unix(-1, 0), time.Unix(1<<32, 0)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expecting panic for time %s", tm)
+				}
+			}()
+			random.NewXIDWithTime(tm)
+		}()
+	}
+}